@@ -7,11 +7,14 @@ import (
 
 	"github.com/fatih/color"
 
+	cache_commands "github.com/letstrygo/letstry/internal/application/commands/cache"
 	editor_commands "github.com/letstrygo/letstry/internal/application/commands/editors"
 	general_commands "github.com/letstrygo/letstry/internal/application/commands/general"
 	hidden_commands "github.com/letstrygo/letstry/internal/application/commands/hidden"
 	session_commands "github.com/letstrygo/letstry/internal/application/commands/sessions"
+	source_commands "github.com/letstrygo/letstry/internal/application/commands/sources"
 	template_commands "github.com/letstrygo/letstry/internal/application/commands/templates"
+	trash_commands "github.com/letstrygo/letstry/internal/application/commands/trash"
 
 	"github.com/letstrygo/letstry/internal/cli"
 	"github.com/letstrygo/letstry/internal/environment"
@@ -54,18 +57,24 @@ func NewApplication(ctx context.Context) *Application {
 		session_commands.ExportSessionCommand(),
 		session_commands.ShowCommand(),
 		session_commands.PruneSessionsCommand(),
+		source_commands.SourcesCommand(),
 
 		template_commands.ListTemplatesCommand(),
 		template_commands.SaveTemplateCommand(),
 		template_commands.ImportTemplate(),
 		template_commands.DeleteTemplateCommand(),
 		template_commands.UpdateTemplateCommand(),
+		template_commands.TemplateCommand(),
 
 		editor_commands.ListEditorsCommand(),
 		editor_commands.SetEditorCommand(),
 		editor_commands.GetEditorCommand(),
+		editor_commands.EditorCommand(),
 
-		hidden_commands.MonitorCommand(),
+		trash_commands.TrashCommand(),
+		cache_commands.CacheCommand(),
+
+		hidden_commands.DaemonCommand(),
 		general_commands.VersionCommand(),
 		general_commands.PathCommand(),
 	}
@@ -77,6 +86,8 @@ func NewApplication(ctx context.Context) *Application {
 			Config: cli.CliAppConfig{
 				DescriptionMaxWidth: 60,
 				HelpCommandSorter:   cli.CommandSorterOrderedAs(commands),
+				CompletionAliases:   []string{"lt", "letstry"},
+				GlobalFlags:         cli.OutputFlags(),
 			},
 
 			Name:             cli.MainName(),
@@ -90,6 +101,9 @@ func NewApplication(ctx context.Context) *Application {
 		app.RegisterCommand(command)
 	}
 
+	// Add shell completion commands
+	app.RegisterCompletionCommands()
+
 	// Add help command
 	app.RegisterHelpCommand()
 
@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"github.com/letstrygo/letstry/internal/cli"
+	"github.com/letstrygo/letstry/internal/manager"
+)
+
+// ArchiveFlags returns the flags used to control how archives are
+// extracted.
+func ArchiveFlags() []cli.Flag {
+	return []cli.Flag{
+		{
+			Name:        "strip-components",
+			Description: "Remove the specified number of leading directories from the files extracted from an archive, such as the top-level directory of a release tarball.",
+			Type:        cli.FlagTypeInt,
+			ValueName:   "n",
+		},
+	}
+}
+
+// ArchiveOptionsFromFlags returns the archive options set using the flags
+// returned by ArchiveFlags.
+func ArchiveOptionsFromFlags(flags cli.FlagValues) manager.ArchiveOptions {
+	return manager.ArchiveOptions{
+		StripComponents: flags.Int("strip-components"),
+	}
+}
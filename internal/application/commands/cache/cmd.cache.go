@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/letstrygo/letstry/internal/application/commands"
+	"github.com/letstrygo/letstry/internal/cli"
+	"github.com/letstrygo/letstry/internal/logging"
+	"github.com/letstrygo/letstry/internal/manager"
+)
+
+func CacheCommand() cli.Command {
+	return cli.Command{
+		Name:             commands.CommandCache.String(),
+		ShortDescription: "List, prune or refresh cached repositories",
+		Description:      "Repositories used as session sources are kept as bare mirrors in the cache, so that new sessions can be cloned from the local mirror instead of the network. This command lists the cached repositories, removes them from the cache, or fetches their latest changes. When no url is provided, prune and refresh apply to every cached repository.",
+		Arguments: []cli.Argument{
+			{
+				Name:        "action",
+				Description: "Can be one of list, prune or refresh. Defaults to \"list\".",
+			},
+			{
+				Name:        "url",
+				Description: "The URL of the cached repository to prune or refresh.",
+				Completer:   completeCachedRepositories,
+			},
+		},
+		Executor: func(ctx context.Context, args []string) error {
+			mgr, err := manager.GetManager(ctx)
+			if err != nil {
+				return err
+			}
+
+			logger, err := logging.LoggerFromContext(ctx)
+			if err != nil {
+				return err
+			}
+
+			var action string = "list"
+			if len(args) > 0 {
+				action = args[0]
+			}
+
+			var url string
+			if len(args) > 1 {
+				url = args[1]
+			}
+
+			switch action {
+			case "list":
+				mirrors, err := mgr.ListRepositoryCache(ctx)
+				if err != nil {
+					return err
+				}
+
+				if len(mirrors) < 1 {
+					logger.Println("cache is empty")
+				}
+
+				output := cli.Output{
+					Data:   mirrors,
+					Header: []string{"URL", "SIZE", "LAST USED", "PATH"},
+				}
+
+				for _, mirror := range mirrors {
+					output.Rows = append(output.Rows, []string{
+						mirror.URL,
+						strconv.FormatInt(mirror.Size, 10),
+						mirror.LastUsed.Format("2006-01-02 15:04:05"),
+						mirror.Path,
+					})
+					output.Lines = append(output.Lines, fmt.Sprintf("cache: %s", mirror.String()))
+				}
+
+				return cli.WriteOutput(ctx, output)
+			case "prune":
+				mirrors, err := mgr.PruneRepositoryCache(ctx, url)
+				if err != nil {
+					return err
+				}
+
+				for _, mirror := range mirrors {
+					logger.Printf("removed %s from cache\n", mirror.URL)
+				}
+
+				return nil
+			case "refresh":
+				mirrors, err := mgr.RefreshRepositoryCache(ctx, url)
+				if err != nil {
+					return err
+				}
+
+				for _, mirror := range mirrors {
+					logger.Printf("refreshed %s\n", mirror.URL)
+				}
+
+				return nil
+			}
+
+			return fmt.Errorf("unknown cache action: %s", action)
+		},
+	}
+}
+
+func completeCachedRepositories(ctx context.Context) ([]string, error) {
+	mgr, err := manager.GetManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mirrors, err := mgr.ListRepositoryCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]string, 0, len(mirrors))
+	for _, mirror := range mirrors {
+		urls = append(urls, mirror.URL)
+	}
+
+	return urls, nil
+}
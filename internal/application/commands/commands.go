@@ -9,7 +9,7 @@ func (c CommandName) String() string {
 const (
 	CommandVersion        CommandName = "version"
 	CommandPath           CommandName = "path"
-	CommandMonitor        CommandName = "monitor"
+	CommandDaemon         CommandName = "daemon"
 	CommandClean          CommandName = "clean"
 	CommandPruneSessions  CommandName = "prune"
 	CommandNewSession     CommandName = "new"
@@ -18,9 +18,14 @@ const (
 	CommandListEditors    CommandName = "editors"
 	CommandGetEditor      CommandName = "get-editor"
 	CommandSetEditor      CommandName = "set-editor"
+	CommandEditor         CommandName = "editor"
 	CommandDeleteTemplate CommandName = "delete"
 	CommandSaveTemplate   CommandName = "save"
 	CommandUpdateTemplate CommandName = "update"
 	CommandExportSession  CommandName = "export"
 	CommandShow           CommandName = "show"
+	CommandTrash          CommandName = "trash"
+	CommandTemplate       CommandName = "template"
+	CommandCache          CommandName = "cache"
+	CommandSources        CommandName = "sources"
 )
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/letstrygo/letstry/internal/manager"
+)
+
+// CompleteTemplates returns the names of the available templates.
+func CompleteTemplates(ctx context.Context) ([]string, error) {
+	mgr, err := manager.GetManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := mgr.ListTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(templates))
+	for _, template := range templates {
+		names = append(names, template.String())
+	}
+
+	return names, nil
+}
+
+// CompleteSessions returns the IDs of the active sessions.
+func CompleteSessions(ctx context.Context) ([]string, error) {
+	mgr, err := manager.GetManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := mgr.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		ids = append(ids, session.ID.String())
+	}
+
+	return ids, nil
+}
+
+// CompleteEditors returns the names of the configured editors.
+func CompleteEditors(ctx context.Context) ([]string, error) {
+	mgr, err := manager.GetManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	editors, err := mgr.ListEditors(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(editors))
+	for _, editor := range editors {
+		names = append(names, editor.Name.String())
+	}
+
+	return names, nil
+}
+
+// CompleteSources returns the names of the available templates and the
+// configured source aliases.
+func CompleteSources(ctx context.Context) ([]string, error) {
+	mgr, err := manager.GetManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := CompleteTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := mgr.ListSourceRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		if rule.Type == manager.SourceRuleTypeAlias {
+			names = append(names, rule.Match)
+		}
+	}
+
+	return names, nil
+}
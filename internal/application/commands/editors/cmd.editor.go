@@ -0,0 +1,160 @@
+package editors
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/letstrygo/letstry/internal/application/commands"
+	"github.com/letstrygo/letstry/internal/cli"
+	"github.com/letstrygo/letstry/internal/config/editors"
+	"github.com/letstrygo/letstry/internal/manager"
+)
+
+func EditorCommand() cli.Command {
+	return cli.Command{
+		Name:             commands.CommandEditor.String(),
+		ShortDescription: "Add, remove or edit an editor",
+		Description:      "This command manages the editors available for new sessions. Adding an editor creates it from the provided flags, while editing an editor only changes the flags that are provided. The path of the editor must exist, or be the name of a command in your PATH.\n\nThe default editor can not be removed, run 'lt set-editor' to change it first.",
+		Arguments: []cli.Argument{
+			{
+				Name:        "action",
+				Description: "Can be one of add, remove or edit.",
+				Required:    true,
+				Completer: func(ctx context.Context) ([]string, error) {
+					return []string{"add", "remove", "edit"}, nil
+				},
+			},
+			{
+				Name:        "editor-name",
+				Description: "The name of the editor.",
+				Required:    true,
+				Completer:   commands.CompleteEditors,
+			},
+		},
+		Flags: []cli.Flag{
+			{
+				Name:        "path",
+				Description: "The path of the editors executable.",
+				Type:        cli.FlagTypeString,
+				ValueName:   "path",
+			},
+			{
+				Name:        "args",
+				Description: "The arguments passed to the editor, split using shell quoting rules.",
+				Type:        cli.FlagTypeString,
+				ValueName:   "args",
+			},
+			{
+				Name:        "run-type",
+				Description: "How the editor is launched. Can be one of run, shell, open or foreground.",
+				Type:        cli.FlagTypeString,
+				ValueName:   "type",
+			},
+			{
+				Name:        "tracking",
+				Description: "How letstry decides that a session has been closed. Can be one of file_access, process or wait. Defaults to file_access when adding an editor.",
+				Type:        cli.FlagTypeString,
+				ValueName:   "type",
+			},
+			{
+				Name:        "capture-delay",
+				Description: "How long to wait before locating the editor process, for example 3s.",
+				Type:        cli.FlagTypeDuration,
+				ValueName:   "duration",
+			},
+			{
+				Name:        "pid-resolution",
+				Description: "How the editor process is located when using process tracking. Can be one of location, tree or launched.",
+				Type:        cli.FlagTypeString,
+				ValueName:   "resolution",
+			},
+			{
+				Name:        "process-name",
+				Description: "Only consider processes whose name contains this value when locating the editor process.",
+				Type:        cli.FlagTypeString,
+				ValueName:   "name",
+			},
+		},
+		Executor: func(ctx context.Context, args []string) error {
+			mgr, err := manager.GetManager(ctx)
+			if err != nil {
+				return err
+			}
+
+			action, editorName := args[0], args[1]
+			flags := cli.FlagsFromContext(ctx)
+
+			switch action {
+			case "add":
+				editor, err := editorFromFlags(flags, editors.Editor{
+					Name:         editors.EditorName(editorName),
+					TrackingType: editors.TrackingTypeFileAccess,
+				})
+				if err != nil {
+					return err
+				}
+
+				return mgr.AddEditor(ctx, editor)
+			case "remove":
+				return mgr.RemoveEditor(ctx, editorName)
+			case "edit":
+				_, err := mgr.UpdateEditor(ctx, editorName, func(editor editors.Editor) (editors.Editor, error) {
+					return editorFromFlags(flags, editor)
+				})
+
+				return err
+			}
+
+			return fmt.Errorf("unknown editor action: %s", action)
+		},
+	}
+}
+
+// editorFromFlags returns the editor with the values of the flags that were
+// provided applied to it.
+func editorFromFlags(flags cli.FlagValues, editor editors.Editor) (editors.Editor, error) {
+	if flags.IsSet("path") {
+		editor.ExecPath = flags.String("path")
+	}
+
+	if flags.IsSet("args") {
+		editor.Args = editors.Arguments(flags.String("args"))
+	}
+
+	if flags.IsSet("run-type") {
+		runType, err := editors.GetRunType(flags.String("run-type"))
+		if err != nil {
+			return editor, err
+		}
+
+		editor.RunType = runType
+	}
+
+	if flags.IsSet("tracking") {
+		trackingType, err := editors.GetTrackingType(flags.String("tracking"))
+		if err != nil {
+			return editor, err
+		}
+
+		editor.TrackingType = trackingType
+	}
+
+	if flags.IsSet("capture-delay") {
+		editor.ProcessCaptureDelay = flags.Duration("capture-delay")
+	}
+
+	if flags.IsSet("pid-resolution") {
+		pidResolution, err := editors.GetPIDResolution(flags.String("pid-resolution"))
+		if err != nil {
+			return editor, err
+		}
+
+		editor.PIDResolution = pidResolution
+	}
+
+	if flags.IsSet("process-name") {
+		editor.ProcessName = flags.String("process-name")
+	}
+
+	return editor, nil
+}
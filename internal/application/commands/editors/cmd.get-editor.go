@@ -2,11 +2,11 @@ package editors
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/fatih/color"
 	"github.com/letstrygo/letstry/internal/application/commands"
 	"github.com/letstrygo/letstry/internal/cli"
-	"github.com/letstrygo/letstry/internal/logging"
 	"github.com/letstrygo/letstry/internal/manager"
 )
 
@@ -21,19 +21,17 @@ func GetEditorCommand() cli.Command {
 				return err
 			}
 
-			logger, err := logging.LoggerFromContext(ctx)
-			if err != nil {
-				return err
-			}
-
 			editor, err := mgr.GetDefaultEditor(ctx)
 			if err != nil {
 				return err
 			}
 
-			logger.Printf("%s: [%s]\n", color.HiWhiteString("default editor"), editor.FullString())
-
-			return nil
+			return cli.WriteOutput(ctx, cli.Output{
+				Data:   editor,
+				Header: editorTableHeader,
+				Rows:   [][]string{editorTableRow(editor)},
+				Lines:  []string{fmt.Sprintf("%s: [%s]", color.HiWhiteString("default editor"), editor.FullString())},
+			})
 		},
 	}
 }
@@ -2,11 +2,11 @@ package editors
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/fatih/color"
 	"github.com/letstrygo/letstry/internal/application/commands"
 	"github.com/letstrygo/letstry/internal/cli"
-	"github.com/letstrygo/letstry/internal/logging"
 	"github.com/letstrygo/letstry/internal/manager"
 )
 
@@ -14,14 +14,27 @@ func ListEditorsCommand() cli.Command {
 	return cli.Command{
 		Name:             commands.CommandListEditors.String(),
 		ShortDescription: "Lists all available editors",
-		Description:      "This command will list all available editors that can be used when creating a new session.",
+		Description:      "This command will list all available editors that can be used when creating a new session.\n\nWith --detect, the installed editors that letstry has a preset for are listed instead, and you will be asked whether each one that is not configured should be added to the configuration.",
+		Flags: []cli.Flag{
+			{
+				Name:        "detect",
+				Description: "Search for installed editors that letstry has a preset for.",
+				Type:        cli.FlagTypeBool,
+			},
+			{
+				Name:        "yes",
+				Short:       "y",
+				Description: "Add the detected editors to the configuration without asking for confirmation.",
+				Type:        cli.FlagTypeBool,
+			},
+		},
 		Executor: func(ctx context.Context, args []string) error {
-			mgr, err := manager.GetManager(ctx)
-			if err != nil {
-				return err
+			flags := cli.FlagsFromContext(ctx)
+			if flags.Bool("detect") {
+				return detectEditors(ctx, flags.Bool("yes"))
 			}
 
-			logger, err := logging.LoggerFromContext(ctx)
+			mgr, err := manager.GetManager(ctx)
 			if err != nil {
 				return err
 			}
@@ -31,11 +44,43 @@ func ListEditorsCommand() cli.Command {
 				return err
 			}
 
+			output := cli.Output{
+				Data:   editors,
+				Header: editorTableHeader,
+			}
+
 			for _, editor := range editors {
-				logger.Printf("%s: [%s]\n", color.HiWhiteString("editor"), editor.FullString())
+				output.Rows = append(output.Rows, editorTableRow(editor))
+				output.Lines = append(output.Lines, fmt.Sprintf("%s: [%s]", color.HiWhiteString("editor"), editor.FullString()))
 			}
 
-			return nil
+			return cli.WriteOutput(ctx, output)
 		},
 	}
 }
+
+func detectEditors(ctx context.Context, yes bool) error {
+	mgr, err := manager.GetManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	detected, err := mgr.DetectEditors(ctx, manager.DetectEditorsArguments{
+		Yes: yes,
+	})
+	if err != nil {
+		return err
+	}
+
+	output := cli.Output{
+		Data:   detected,
+		Header: append(editorTableHeader, "CONFIGURED"),
+	}
+
+	for _, editor := range detected {
+		output.Rows = append(output.Rows, append(editorTableRow(editor.Editor), fmt.Sprintf("%t", editor.Configured)))
+		output.Lines = append(output.Lines, fmt.Sprintf("%s: [%s], configured=%t", color.HiWhiteString("editor"), editor.FullString(), editor.Configured))
+	}
+
+	return cli.WriteOutput(ctx, output)
+}
@@ -17,12 +17,13 @@ func SetEditorCommand() cli.Command {
 	return cli.Command{
 		Name:             commands.CommandSetEditor.String(),
 		ShortDescription: "Set the default editor",
-		Description:      "This command sets the default editor to use for new sessions. You can run 'lt editors' for a list of available editors.\n\nAdd new editors by editing the configuration file directly.",
+		Description:      "This command sets the default editor to use for new sessions. You can run 'lt editors' for a list of available editors.\n\nAdd new editors using 'lt editor add'.",
 		Arguments: []cli.Argument{
 			{
 				Name:        "editor-name",
 				Description: "The name of the editor to use.",
 				Required:    true,
+				Completer:   commands.CompleteEditors,
 			},
 		},
 		Executor: func(ctx context.Context, args []string) error {
@@ -0,0 +1,17 @@
+package editors
+
+import (
+	"github.com/letstrygo/letstry/internal/config/editors"
+)
+
+var editorTableHeader = []string{"NAME", "RUN TYPE", "PATH", "ARGS", "TRACKING"}
+
+func editorTableRow(editor editors.Editor) []string {
+	return []string{
+		editor.Name.String(),
+		editor.RunTypeOrDefault().String(),
+		editor.ExecPath,
+		editor.Args.String(),
+		editor.TrackingType.String(),
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/letstrygo/letstry/internal/application/commands"
 	"github.com/letstrygo/letstry/internal/cli"
 	"github.com/letstrygo/letstry/internal/config"
+	"github.com/letstrygo/letstry/internal/logging"
 	"github.com/letstrygo/letstry/internal/storage"
 )
 
@@ -26,35 +27,44 @@ func PathCommand() cli.Command {
 				return err
 			}
 
+			logger, err := logging.LoggerFromContext(ctx)
+			if err != nil {
+				return err
+			}
+
 			var opt string
 			if len(args) > 0 {
 				opt = args[0]
 			}
 
+			var path string
+
 			switch opt {
 			case "sessions":
 				store := storage.GetStorage()
-				sessPath := store.GetAbsolutePath("sessions.json")
-				println(sessPath)
-				return nil
+				path = store.GetAbsolutePath("sessions.json")
 			case "projects":
-				path := cfg.LTPath
 				if cfg.RequireExport {
-					println("require-export enabled, all projects will be stored in a temporary directory and deleted once their corresponding session is terminated.")
+					logger.Println("require-export enabled, all projects will be stored in a temporary directory and deleted once their corresponding session is terminated.")
 					return nil
 				}
 
-				if path == "" {
-					println("project-path: custom projects_path not set, all projects will be stored in a temporary directory and deleted once their corresponding session is terminated.")
+				if cfg.LTPath == "" {
+					logger.Println("project-path: custom projects_path not set, all projects will be stored in a temporary directory and deleted once their corresponding session is terminated.")
 					return nil
 				}
 
-				println(cfg.LTPath)
-				return nil
+				path = cfg.LTPath
 			default:
-				println(cfg.Path())
-				return nil
+				path = cfg.Path()
 			}
+
+			return cli.WriteOutput(ctx, cli.Output{
+				Data:   path,
+				Header: []string{"PATH"},
+				Rows:   [][]string{{path}},
+				Lines:  []string{path},
+			})
 		},
 	}
 }
@@ -11,6 +11,13 @@ import (
 	"github.com/letstrygo/letstry/internal/cli"
 )
 
+type versionInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	OS      string `json:"os"`
+	Arch    string `json:"arch"`
+}
+
 func VersionCommand() cli.Command {
 	return cli.Command{
 		Name:             commands.CommandVersion.String(),
@@ -28,9 +35,19 @@ func VersionCommand() cli.Command {
 				return fmt.Errorf("failed to read build info: %v", err)
 			}
 
-			fmt.Println(cli.MainName(), "version", info.Main.Version, fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH))
+			version := versionInfo{
+				Name:    cli.MainName(),
+				Version: info.Main.Version,
+				OS:      runtime.GOOS,
+				Arch:    runtime.GOARCH,
+			}
 
-			return nil
+			return cli.WriteOutput(ctx, cli.Output{
+				Data:   version,
+				Header: []string{"NAME", "VERSION", "OS", "ARCH"},
+				Rows:   [][]string{{version.Name, version.Version, version.OS, version.Arch}},
+				Lines:  []string{fmt.Sprintf("%s version %s %s/%s", version.Name, version.Version, version.OS, version.Arch)},
+			})
 		},
 	}
 }
@@ -0,0 +1,99 @@
+package hidden
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/letstrygo/letstry/internal/application/commands"
+	"github.com/letstrygo/letstry/internal/cli"
+	"github.com/letstrygo/letstry/internal/logging"
+	"github.com/letstrygo/letstry/internal/manager"
+)
+
+func DaemonCommand() cli.Command {
+	return cli.Command{
+		Name:             commands.CommandDaemon.String(),
+		ShortDescription: "Run the session supervisor daemon",
+		Description:      "This command runs the background daemon that supervises every session, removing them once their editor has been closed. The daemon is started automatically when a new session is created, so it is not usually necessary to run this command directly.",
+		Arguments: []cli.Argument{
+			{
+				Name:        "action",
+				Description: "Can be one of run, status or stop. Defaults to \"run\".",
+			},
+		},
+		Executor: func(ctx context.Context, args []string) error {
+			mgr, err := manager.GetManager(ctx)
+			if err != nil {
+				return err
+			}
+
+			var action string = "run"
+			if len(args) > 0 {
+				action = args[0]
+			}
+
+			switch action {
+			case "run":
+				// The daemon runs in the background, so it logs to the log
+				// file rather than the console.
+				logger, err := logging.New(&logging.LoggerConfig{
+					LogMode: logging.LogModeFile,
+				})
+				if err != nil {
+					return err
+				}
+
+				ctx = logging.ContextWithLogger(ctx, logger)
+				defer logging.CloseLog(ctx)
+
+				return mgr.RunDaemon(ctx)
+			case "status":
+				statuses, err := mgr.DaemonStatus(ctx)
+				if err != nil {
+					return err
+				}
+
+				if len(statuses) < 1 {
+					logger, err := logging.LoggerFromContext(ctx)
+					if err != nil {
+						return err
+					}
+
+					logger.Println("daemon running, no sessions supervised")
+				}
+
+				output := cli.Output{
+					Data:   statuses,
+					Header: []string{"ID", "STATE", "MONITOR AFTER", "LOCATION"},
+				}
+
+				for _, status := range statuses {
+					output.Rows = append(output.Rows, []string{
+						status.Session.ID.String(),
+						status.State.String(),
+						status.MonitorAfter.Format("2006-01-02 15:04:05"),
+						status.Session.Location,
+					})
+					output.Lines = append(output.Lines, fmt.Sprintf("session: %s, state=%s", status.Session.String(), status.State))
+				}
+
+				return cli.WriteOutput(ctx, output)
+			case "stop":
+				err := mgr.StopDaemon(ctx)
+				if err != nil {
+					return err
+				}
+
+				logger, err := logging.LoggerFromContext(ctx)
+				if err != nil {
+					return err
+				}
+
+				logger.Println("daemon stopped")
+				return nil
+			}
+
+			return fmt.Errorf("unknown daemon action: %s", action)
+		},
+	}
+}
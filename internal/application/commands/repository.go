@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"github.com/letstrygo/letstry/internal/cli"
+	"github.com/letstrygo/letstry/internal/manager"
+)
+
+// RepositoryFlags returns the flags used to control how git repositories
+// are cloned.
+func RepositoryFlags() []cli.Flag {
+	return []cli.Flag{
+		{
+			Name:        "ref",
+			Description: "The branch, tag or commit SHA to check out when the source is a git repository.",
+			Type:        cli.FlagTypeString,
+			ValueName:   "ref",
+		},
+		{
+			Name:        "depth",
+			Description: "Truncate the history of the git repository to the specified number of commits.",
+			Type:        cli.FlagTypeInt,
+		},
+		{
+			Name:        "recurse-submodules",
+			Description: "Clone the submodules of the git repository as well.",
+			Type:        cli.FlagTypeBool,
+		},
+		{
+			Name:        "subdir",
+			Description: "Only use the specified directory of the git repository.",
+			Type:        cli.FlagTypeString,
+			ValueName:   "path",
+		},
+	}
+}
+
+// RepositoryOptionsFromFlags returns the repository options set using the
+// flags returned by RepositoryFlags.
+func RepositoryOptionsFromFlags(flags cli.FlagValues) manager.RepositoryOptions {
+	return manager.RepositoryOptions{
+		Ref:               flags.String("ref"),
+		Depth:             flags.Int("depth"),
+		RecurseSubmodules: flags.Bool("recurse-submodules"),
+		Subdir:            flags.String("subdir"),
+	}
+}
@@ -2,6 +2,8 @@ package sessions
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	"github.com/letstrygo/letstry/internal/application/commands"
 	"github.com/letstrygo/letstry/internal/cli"
@@ -9,6 +11,18 @@ import (
 	"github.com/letstrygo/letstry/internal/manager"
 )
 
+var sessionTableHeader = []string{"ID", "EDITOR", "SOURCE", "PID", "LOCATION"}
+
+func sessionTableRow(session manager.Session) []string {
+	return []string{
+		session.ID.String(),
+		session.Editor.Name.String(),
+		session.Source.String(),
+		strconv.Itoa(session.PID),
+		session.Location,
+	}
+}
+
 func ListSessionsCommand() cli.Command {
 	return cli.Command{
 		Name:             commands.CommandListSessions.String(),
@@ -32,14 +46,19 @@ func ListSessionsCommand() cli.Command {
 
 			if len(sessions) < 1 {
 				logger.Println("no sessions found")
-				return nil
+			}
+
+			output := cli.Output{
+				Data:   sessions,
+				Header: sessionTableHeader,
 			}
 
 			for _, session := range sessions {
-				logger.Printf("session: %s\n", session.String())
+				output.Rows = append(output.Rows, sessionTableRow(session))
+				output.Lines = append(output.Lines, fmt.Sprintf("session: %s", session.String()))
 			}
 
-			return nil
+			return cli.WriteOutput(ctx, output)
 		},
 	}
 }
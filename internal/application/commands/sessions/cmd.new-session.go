@@ -5,6 +5,7 @@ import (
 
 	"github.com/letstrygo/letstry/internal/application/commands"
 	"github.com/letstrygo/letstry/internal/cli"
+	"github.com/letstrygo/letstry/internal/config/editors"
 	"github.com/letstrygo/letstry/internal/logging"
 	"github.com/letstrygo/letstry/internal/manager"
 )
@@ -18,25 +19,44 @@ func NewSessionCommand() cli.Command {
 		Arguments: []cli.Argument{
 			{
 				Name:        "source",
-				Description: "The source to use for the new session or project. Can be a git repository URL, a path to a directory, or the name of a letstry template.\n\nIf source is not provided, the session will be created from a blank source.",
+				Description: "The source to use for the new session or project. Can be a git repository URL, a path to a directory, a .tar.gz, .tgz or .zip archive (a local file or an http(s) URL), or the name of a letstry template. Prefix the source with tpl:, dir:, git: or archive: to choose its type explicitly.\n\nIf source is not provided, the session will be created from a blank source.",
+				Completer:   commands.CompleteSources,
 			},
+		},
+		Flags: append([]cli.Flag{
 			{
-				Name:        "--temp",
+				Name:        "temp",
 				Description: "When set, session will be forcibly stored in a temporary location. This overrides the \"Require Export\" field in your config file.",
+				Type:        cli.FlagTypeBool,
 			},
-		},
+			{
+				Name:        "set",
+				Description: "Sets the value of a variable declared in the templates manifest.",
+				Type:        cli.FlagTypeStringSlice,
+				ValueName:   "key=value",
+			},
+			{
+				Name:        "values",
+				Description: "Path to a JSON file containing values for the variables declared in the templates manifest.",
+				Type:        cli.FlagTypeString,
+				ValueName:   "file",
+			},
+		}, append(commands.RepositoryFlags(), commands.ArchiveFlags()...)...),
 		Executor: func(ctx context.Context, args []string) error {
 			var source string
-			var forceRequireExport bool
-
 			if len(args) > 0 {
 				source = args[0]
 			}
-			if source == "--temp" {
-				source = ""
-				forceRequireExport = true
-			} else if len(args) > 1 {
-				forceRequireExport = args[1] == "--temp"
+
+			flags := cli.FlagsFromContext(ctx)
+
+			values := map[string]string{}
+			for _, v := range flags.StringSlice("set") {
+				key, value, err := manager.ParseTemplateValue(v)
+				if err != nil {
+					return err
+				}
+				values[key] = value
 			}
 
 			mgr, err := manager.GetManager(ctx)
@@ -51,13 +71,19 @@ func NewSessionCommand() cli.Command {
 
 			session, err := mgr.CreateSession(ctx, manager.CreateSessionArguments{
 				Source:             source,
-				ForceRequireExport: forceRequireExport,
+				ForceRequireExport: flags.Bool("temp"),
+				Values:             values,
+				ValuesFile:         flags.String("values"),
+				Repository:         commands.RepositoryOptionsFromFlags(flags),
+				Archive:            commands.ArchiveOptionsFromFlags(flags),
 			})
 			if err != nil {
 				return err
 			}
 
-			if session != nil {
+			if session != nil && session.Editor.TrackingTypeOrDefault() == editors.TrackingTypeWait {
+				logger.Printf("session ended: %s\n", session.String())
+			} else if session != nil {
 				logger.Printf("session created: %s\n", session.String())
 			} else {
 				logger.Printf("project created")
@@ -25,6 +25,7 @@ func PruneSessionsCommand() cli.Command {
 				Name:        "session-id",
 				Description: "The session to prune. (Defaults to all inactive sessions)",
 				Required:    false,
+				Completer:   commands.CompleteSessions,
 			},
 		},
 		Executor: func(ctx context.Context, args []string) error {
@@ -52,9 +53,11 @@ func PruneSessionsCommand() cli.Command {
 				sessions = []manager.Session{sess}
 			}
 
+			active := manager.ActiveSessions(sessions)
+
 			inactiveSessions := []manager.Session{}
 			for _, session := range sessions {
-				if !session.IsActive() {
+				if !active[session.ID] {
 					inactiveSessions = append(inactiveSessions, session)
 				}
 			}
@@ -2,6 +2,8 @@ package sessions
 
 import (
 	"context"
+	"encoding/json"
+	"strconv"
 
 	"github.com/letstrygo/letstry/internal/application/commands"
 	"github.com/letstrygo/letstry/internal/cli"
@@ -20,35 +22,28 @@ func ShowCommand() cli.Command {
 				Name:        "session-id",
 				Description: "The session ID to show information for",
 				Required:    false,
+				Completer:   commands.CompleteSessions,
 			},
+		},
+		Flags: []cli.Flag{
 			{
-				Name:        "output",
-				Description: "Either 'full' (default), 'path', 'pid', 'editor' or 'json'",
-				Required:    false,
+				Name:        "display",
+				Short:       "d",
+				Description: "Either 'full', 'location', 'pid', 'editor' or 'json'.",
+				Type:        cli.FlagTypeString,
+				Default:     string(manager.SessionDisplayTypeFull),
 			},
 		},
 		Executor: func(ctx context.Context, args []string) error {
-			var (
-				displayType manager.SessionDisplayType = manager.SessionDisplayTypeFull
-				sessionID   *identifier.ID
-			)
+			var sessionID *identifier.ID
 
 			if len(args) > 0 {
-				// If first arg is a display type, don't treat it as an ID
-				if dt, err := manager.ParseSessionDisplayType(args[0]); err == nil {
-					displayType = dt
-				} else {
-					sessionID = identifier.ParseIDPtr(args[0])
-				}
+				sessionID = identifier.ParseIDPtr(args[0])
 			}
 
-			if len(args) > 1 {
-				dt, err := manager.ParseSessionDisplayType(args[1])
-				if err != nil {
-					return err
-				}
-
-				displayType = dt
+			displayType, err := manager.ParseSessionDisplayType(cli.FlagsFromContext(ctx).String("display"))
+			if err != nil {
+				return err
 			}
 
 			mgr, err := manager.GetManager(ctx)
@@ -56,9 +51,34 @@ func ShowCommand() cli.Command {
 				return err
 			}
 
-			return mgr.ShowSession(ctx, manager.ShowSessionArguments{
-				SessionID:  sessionID,
-				OutputType: displayType,
+			session, err := mgr.FindSession(ctx, sessionID)
+			if err != nil {
+				return err
+			}
+
+			var line string
+			switch displayType {
+			case manager.SessionDisplayTypeFull:
+				line = session.String()
+			case manager.SessionDisplayTypeLocation:
+				line = session.Location
+			case manager.SessionDisplayTypePID:
+				line = strconv.Itoa(session.PID)
+			case manager.SessionDisplayTypeEditor:
+				line = session.Editor.FullString()
+			case manager.SessionDisplayTypeJSON:
+				data, err := json.MarshalIndent(session, "", "    ")
+				if err != nil {
+					return err
+				}
+				line = string(data)
+			}
+
+			return cli.WriteOutput(ctx, cli.Output{
+				Data:   session,
+				Header: sessionTableHeader,
+				Rows:   [][]string{sessionTableRow(session)},
+				Lines:  []string{line},
 			})
 		},
 	}
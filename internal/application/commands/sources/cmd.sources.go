@@ -0,0 +1,81 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/letstrygo/letstry/internal/application/commands"
+	"github.com/letstrygo/letstry/internal/cli"
+	"github.com/letstrygo/letstry/internal/logging"
+	"github.com/letstrygo/letstry/internal/manager"
+)
+
+func SourcesCommand() cli.Command {
+	return cli.Command{
+		Name:             commands.CommandSources.String(),
+		ShortDescription: "List the configured source aliases and rewrite rules",
+		Description:      "Source aliases replace a session source that exactly matches their name, and rewrite rules expand sources that start with their prefix. Both are configured in the sources field of your config file. When a source is provided, the source it resolves to is displayed instead.",
+		Arguments: []cli.Argument{
+			{
+				Name:        "source",
+				Description: "A session source to resolve.",
+				Completer:   commands.CompleteSources,
+			},
+		},
+		Executor: func(ctx context.Context, args []string) error {
+			mgr, err := manager.GetManager(ctx)
+			if err != nil {
+				return err
+			}
+
+			logger, err := logging.LoggerFromContext(ctx)
+			if err != nil {
+				return err
+			}
+
+			if len(args) > 0 {
+				source, opts, err := mgr.ResolveSource(ctx, args[0])
+				if err != nil {
+					return err
+				}
+
+				return cli.WriteOutput(ctx, cli.Output{
+					Data: struct {
+						manager.Source
+						Repository manager.RepositoryOptions `json:"repository"`
+					}{source, opts},
+					Header: []string{"TYPE", "SOURCE", "REF", "SUBDIR"},
+					Rows:   [][]string{{source.SourceType.String(), source.Value, opts.Ref, opts.Subdir}},
+					Lines:  []string{fmt.Sprintf("source: %s", source.FormattedValue())},
+				})
+			}
+
+			rules, err := mgr.ListSourceRules(ctx)
+			if err != nil {
+				return err
+			}
+
+			if len(rules) < 1 {
+				logger.Println("no source aliases or rewrite rules configured")
+			}
+
+			output := cli.Output{
+				Data:   rules,
+				Header: []string{"TYPE", "MATCH", "SOURCE", "REF", "SUBDIR"},
+			}
+
+			for _, rule := range rules {
+				output.Rows = append(output.Rows, []string{
+					rule.Type.String(),
+					rule.Match,
+					rule.Source,
+					rule.Ref,
+					rule.Subdir,
+				})
+				output.Lines = append(output.Lines, fmt.Sprintf("source: %s", rule.String()))
+			}
+
+			return cli.WriteOutput(ctx, output)
+		},
+	}
+}
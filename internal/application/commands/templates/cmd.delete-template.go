@@ -27,6 +27,7 @@ func DeleteTemplateCommand() cli.Command {
 				Name:        "template-name",
 				Description: "The name of the template to delete.",
 				Required:    true,
+				Completer:   commands.CompleteTemplates,
 			},
 		},
 		Executor: func(ctx context.Context, args []string) error {
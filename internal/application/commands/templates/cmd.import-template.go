@@ -4,19 +4,20 @@ import (
 	"context"
 	"errors"
 
+	"github.com/letstrygo/letstry/internal/application/commands"
 	"github.com/letstrygo/letstry/internal/cli"
 	"github.com/letstrygo/letstry/internal/manager"
 )
 
 var (
-	ErrMissingRepository = errors.New("missing repository")
+	ErrMissingSource = errors.New("missing repository or archive")
 )
 
 func ImportTemplate() cli.Command {
 	return cli.Command{
 		Name:             "import",
-		ShortDescription: "Import a template from a git repository",
-		Description:      "This command allows you to import a template from a git repository.",
+		ShortDescription: "Import a template from a git repository or archive",
+		Description:      "This command allows you to import a template from a git repository, or from a .tar.gz, .tgz or .zip archive. Archives can be local files or http(s) URLs.",
 		Arguments: []cli.Argument{
 			{
 				Name:        "template-name",
@@ -24,11 +25,12 @@ func ImportTemplate() cli.Command {
 				Required:    true,
 			},
 			{
-				Name:        "repository",
-				Description: "The git repository to import the template from.",
+				Name:        "source",
+				Description: "The git repository or archive to import the template from.",
 				Required:    true,
 			},
 		},
+		Flags: append(commands.RepositoryFlags(), commands.ArchiveFlags()...),
 		Executor: func(ctx context.Context, args []string) error {
 			mgr, err := manager.GetManager(ctx)
 			if err != nil {
@@ -42,14 +44,17 @@ func ImportTemplate() cli.Command {
 			templateName := args[0]
 
 			if len(args) < 2 {
-				return ErrMissingRepository
+				return ErrMissingSource
 			}
 
-			repository := args[1]
+			source := args[1]
+			flags := cli.FlagsFromContext(ctx)
 
 			_, err = mgr.ImportTemplate(ctx, manager.ImportTemplateArguments{
-				TemplateName:  templateName,
-				RepositoryUrl: repository,
+				TemplateName: templateName,
+				Source:       source,
+				Repository:   commands.RepositoryOptionsFromFlags(flags),
+				Archive:      commands.ArchiveOptionsFromFlags(flags),
 			})
 			if err != nil {
 				return err
@@ -2,6 +2,7 @@ package templates
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/letstrygo/letstry/internal/application/commands"
 	"github.com/letstrygo/letstry/internal/cli"
@@ -32,14 +33,28 @@ func ListTemplatesCommand() cli.Command {
 
 			if len(templates) < 1 {
 				logger.Println("no templates found")
-				return nil
 			}
 
+			output := cli.Output{
+				Header: []string{"NAME", "UPDATED", "PATH"},
+			}
+
+			details := []manager.TemplateDetails{}
 			for _, template := range templates {
-				logger.Printf("template: %s\n", template.FormattedString(ctx))
+				d := template.Details(ctx)
+				details = append(details, d)
+
+				updated := "unknown"
+				if d.Updated != nil {
+					updated = d.Updated.Format("2006-01-02 15:04:05")
+				}
+
+				output.Rows = append(output.Rows, []string{d.Name, updated, d.Path})
+				output.Lines = append(output.Lines, fmt.Sprintf("template: %s", template.FormattedString(ctx)))
 			}
+			output.Data = details
 
-			return nil
+			return cli.WriteOutput(ctx, output)
 		},
 	}
 }
@@ -12,7 +12,7 @@ func SaveTemplateCommand() cli.Command {
 	return cli.Command{
 		Name:                 commands.CommandSaveTemplate.String(),
 		ShortDescription:     "Saves the current session as a template",
-		Description:          "This command must be run from within a session. It will save the current session as a template with the specified name. If no name is provided, and the session was created from a template, the template's name will be used.",
+		Description:          "This command must be run from within a session. It will save the current session as a template with the specified name. If no name is provided, and the session was created from a template, the template's name will be used.\n\nIf a template with the same name already exists and the session was not created from it, you will be asked to confirm before it is overwritten.",
 		MustBeRunFromSession: true,
 		Arguments: []cli.Argument{
 			{
@@ -20,6 +20,14 @@ func SaveTemplateCommand() cli.Command {
 				Description: "The name to use for the template. If not provided, and the session was created from a template, the template's name will be used.",
 			},
 		},
+		Flags: []cli.Flag{
+			{
+				Name:        "yes",
+				Short:       "y",
+				Description: "Overwrite an existing template without asking for confirmation.",
+				Type:        cli.FlagTypeBool,
+			},
+		},
 		Executor: func(ctx context.Context, args []string) error {
 			var templateName string
 
@@ -34,6 +42,7 @@ func SaveTemplateCommand() cli.Command {
 
 			_, err = mgr.SaveSessionAsTemplate(ctx, manager.SaveSessionAsTemplateArguments{
 				TemplateName: templateName,
+				Overwrite:    cli.FlagsFromContext(ctx).Bool("yes"),
 			})
 
 			if err != nil {
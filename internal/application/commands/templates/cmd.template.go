@@ -0,0 +1,100 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/letstrygo/letstry/internal/application/commands"
+	"github.com/letstrygo/letstry/internal/cli"
+	"github.com/letstrygo/letstry/internal/logging"
+	"github.com/letstrygo/letstry/internal/manager"
+)
+
+var (
+	ErrMissingRevision = errors.New("missing revision")
+)
+
+func TemplateCommand() cli.Command {
+	return cli.Command{
+		Name:             commands.CommandTemplate.String(),
+		ShortDescription: "Show the history of a template or roll it back",
+		Description:      "Every time a template is saved a new revision is recorded in the templates history. This command lists the revisions of a template, or restores the template to an earlier revision. Rolling back is recorded as a new revision, so it can be undone.\n\nYou can create a session from an earlier revision using 'lt new <template-name>@<rev>'.",
+		Arguments: []cli.Argument{
+			{
+				Name:        "action",
+				Description: "Can be one of history or rollback.",
+				Required:    true,
+				Completer: func(ctx context.Context) ([]string, error) {
+					return []string{"history", "rollback"}, nil
+				},
+			},
+			{
+				Name:        "template-name",
+				Description: "The name of the template.",
+				Required:    true,
+				Completer:   commands.CompleteTemplates,
+			},
+			{
+				Name:        "rev",
+				Description: "The revision to roll back to.",
+			},
+		},
+		Executor: func(ctx context.Context, args []string) error {
+			mgr, err := manager.GetManager(ctx)
+			if err != nil {
+				return err
+			}
+
+			logger, err := logging.LoggerFromContext(ctx)
+			if err != nil {
+				return err
+			}
+
+			action, templateName := args[0], args[1]
+
+			switch action {
+			case "history":
+				revisions, err := mgr.TemplateHistory(ctx, templateName)
+				if err != nil {
+					return err
+				}
+
+				if len(revisions) < 1 {
+					logger.Printf("template %s has no revisions\n", templateName)
+				}
+
+				output := cli.Output{
+					Data:   revisions,
+					Header: []string{"REV", "DATE", "AUTHOR", "MESSAGE"},
+				}
+
+				for _, revision := range revisions {
+					output.Rows = append(output.Rows, []string{
+						revision.ShortHash(),
+						revision.Date.Format("2006-01-02 15:04:05"),
+						revision.Author,
+						revision.Message,
+					})
+					output.Lines = append(output.Lines, fmt.Sprintf("revision: %s", revision.String()))
+				}
+
+				return cli.WriteOutput(ctx, output)
+			case "rollback":
+				if len(args) < 3 {
+					return ErrMissingRevision
+				}
+
+				revision, err := mgr.RollbackTemplate(ctx, templateName, args[2])
+				if err != nil {
+					return err
+				}
+
+				logger.Printf("rolled back template %s to %s\n", templateName, revision.ShortHash())
+				return nil
+			}
+
+			return fmt.Errorf("unknown template action: %s", action)
+		},
+	}
+}
@@ -20,6 +20,7 @@ func UpdateTemplateCommand() cli.Command {
 				Name:        "template-name",
 				Description: "The name of the template to update.",
 				Required:    true,
+				Completer:   commands.CompleteTemplates,
 			},
 		},
 		Executor: func(ctx context.Context, args []string) error {
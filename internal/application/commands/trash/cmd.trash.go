@@ -0,0 +1,96 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/letstrygo/letstry/internal/application/commands"
+	"github.com/letstrygo/letstry/internal/cli"
+	"github.com/letstrygo/letstry/internal/logging"
+	"github.com/letstrygo/letstry/internal/manager"
+	"github.com/letstrygo/letstry/internal/util/identifier"
+)
+
+var (
+	ErrMissingTrashID = errors.New("missing trash entry id")
+)
+
+func TrashCommand() cli.Command {
+	return cli.Command{
+		Name:             commands.CommandTrash.String(),
+		ShortDescription: "List or restore deleted sessions and templates",
+		Description:      "Deleted sessions and templates are moved to the trash, where they are kept until they exceed the retention configured in your config file. This command lists the contents of the trash, or restores an entry to its original location. Restored sessions are re-opened in their editor.",
+		Arguments: []cli.Argument{
+			{
+				Name:        "action",
+				Description: "Can be one of list or restore. Defaults to \"list\".",
+			},
+			{
+				Name:        "id",
+				Description: "The ID of the trash entry to restore.",
+			},
+		},
+		Executor: func(ctx context.Context, args []string) error {
+			mgr, err := manager.GetManager(ctx)
+			if err != nil {
+				return err
+			}
+
+			logger, err := logging.LoggerFromContext(ctx)
+			if err != nil {
+				return err
+			}
+
+			var action string = "list"
+			if len(args) > 0 {
+				action = args[0]
+			}
+
+			switch action {
+			case "list":
+				entries, err := mgr.ListTrash(ctx)
+				if err != nil {
+					return err
+				}
+
+				if len(entries) < 1 {
+					logger.Println("trash is empty")
+				}
+
+				output := cli.Output{
+					Data:   entries,
+					Header: []string{"ID", "TYPE", "DELETED", "SIZE", "ORIGINAL PATH"},
+				}
+
+				for _, entry := range entries {
+					output.Rows = append(output.Rows, []string{
+						entry.ID.String(),
+						entry.Type.String(),
+						entry.DeletedAt.Format("2006-01-02 15:04:05"),
+						strconv.FormatInt(entry.Size, 10),
+						entry.OriginalPath,
+					})
+					output.Lines = append(output.Lines, fmt.Sprintf("trash: %s", entry.String()))
+				}
+
+				return cli.WriteOutput(ctx, output)
+			case "restore":
+				if len(args) < 2 {
+					return ErrMissingTrashID
+				}
+
+				entry, err := mgr.RestoreTrashEntry(ctx, identifier.ID(args[1]))
+				if err != nil {
+					return err
+				}
+
+				logger.Printf("restored %s to %s\n", entry.Type, entry.OriginalPath)
+				return nil
+			}
+
+			return fmt.Errorf("unknown trash action: %s", action)
+		},
+	}
+}
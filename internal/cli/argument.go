@@ -1,15 +1,21 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/fatih/color"
 )
 
+// ArgumentCompleter returns the candidate values for an argument when
+// generating shell completions.
+type ArgumentCompleter func(ctx context.Context) ([]string, error)
+
 type Argument struct {
 	Name        string
 	Description string
 	Required    bool
+	Completer   ArgumentCompleter
 }
 
 func (a Argument) Label() string {
@@ -14,6 +14,11 @@ import (
 type CliAppConfig struct {
 	DescriptionMaxWidth int
 	HelpCommandSorter   CommandSorter
+	// Additional program names, such as shell aliases, that the generated
+	// completion scripts are registered for.
+	CompletionAliases []string
+	// Flags accepted by every command.
+	GlobalFlags []Flag
 }
 
 type CliApp struct {
@@ -89,6 +94,10 @@ func (app *CliApp) registerCommand(command Command) error {
 		return fmt.Errorf("command %s already registered", command.Name)
 	}
 
+	if !command.RawArguments {
+		command.Flags = append(slices.Clone(command.Flags), app.Config.GlobalFlags...)
+	}
+
 	app.commands[command.Name] = command
 
 	return nil
@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+const (
+	CompletionCommandName string = "completion"
+	completeCommandName   string = "__complete"
+)
+
+var completionShells = map[string]string{
+	"bash": bashCompletionTemplate,
+	"zsh":  zshCompletionTemplate,
+	"fish": fishCompletionTemplate,
+}
+
+func completionCommand(app *CliApp) Command {
+	return Command{
+		Name:             CompletionCommandName,
+		ShortDescription: "Generate shell completion scripts",
+		Description:      "Generates a completion script for the specified shell. Load it in your shell's startup file, for example: 'source <(" + app.Name + " completion bash)'.",
+		Arguments: []Argument{
+			{
+				Name:        "shell",
+				Description: "Can be one of bash, zsh or fish.",
+				Required:    true,
+				Completer: func(ctx context.Context) ([]string, error) {
+					return []string{"bash", "zsh", "fish"}, nil
+				},
+			},
+		},
+		Executor: func(ctx context.Context, args []string) error {
+			script, ok := completionShells[args[0]]
+			if !ok {
+				return fmt.Errorf("unsupported shell: %s", args[0])
+			}
+
+			return template.Must(
+				template.
+					New(fmt.Sprintf("completion.%s", args[0])).
+					Funcs(defaultTemplateFuncs(*app)).
+					Parse(script),
+			).Execute(os.Stdout, app)
+		},
+	}
+}
+
+// completeCommand is invoked by the completion scripts with the words
+// following the program name, the last of which is the word being completed.
+// It prints the matching candidates, one per line.
+func completeCommand(app *CliApp) Command {
+	return Command{
+		Name:             completeCommandName,
+		ShortDescription: "Print completion candidates",
+		Description:      "This command is used by the shell completion scripts. It is not intended to be run directly by the user.",
+		Hidden:           true,
+		RawArguments:     true,
+		Executor: func(ctx context.Context, args []string) error {
+			for _, candidate := range app.complete(ctx, args) {
+				fmt.Println(candidate)
+			}
+
+			return nil
+		},
+	}
+}
+
+// complete returns the candidates for the last of the given words.
+func (app *CliApp) complete(ctx context.Context, words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+
+	current := words[len(words)-1]
+
+	if len(words) == 1 {
+		return filterPrefix(app.commandNames(), current)
+	}
+
+	command, err := app.Command(words[0])
+	if err != nil {
+		return nil
+	}
+
+	if strings.HasPrefix(current, "-") {
+		candidates := []string{}
+		for _, flag := range command.Flags {
+			candidates = append(candidates, "--"+flag.Name)
+			if flag.Short != "" {
+				candidates = append(candidates, "-"+flag.Short)
+			}
+		}
+
+		return filterPrefix(candidates, current)
+	}
+
+	// Determine which positional argument is being completed, skipping
+	// over flags and their values.
+	position := 0
+	preceding := words[1 : len(words)-1]
+	for i := 0; i < len(preceding); i++ {
+		word := preceding[i]
+		if !strings.HasPrefix(word, "-") || word == "-" {
+			position++
+			continue
+		}
+
+		name, _, hasValue := strings.Cut(strings.TrimLeft(word, "-"), "=")
+		flag, ok := lookupFlag(command.Flags, name, !strings.HasPrefix(word, "--"))
+		if ok && !hasValue && flag.Type != FlagTypeBool {
+			// The next word is the flag's value.
+			if i == len(preceding)-1 {
+				return nil
+			}
+			i++
+		}
+	}
+
+	if position >= len(command.Arguments) || command.Arguments[position].Completer == nil {
+		return nil
+	}
+
+	candidates, err := command.Arguments[position].Completer(ctx)
+	if err != nil {
+		return nil
+	}
+
+	return filterPrefix(candidates, current)
+}
+
+// commandNames returns the names and aliases of every visible command.
+func (app *CliApp) commandNames() []string {
+	names := []string{}
+	for _, command := range commands(*app) {
+		names = append(names, command.Name)
+		names = append(names, command.Aliases...)
+	}
+
+	return names
+}
+
+func filterPrefix(values []string, prefix string) []string {
+	result := []string{}
+	for _, value := range values {
+		if strings.HasPrefix(value, prefix) && !slices.Contains(result, value) {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
+
+func (app *CliApp) RegisterCompletionCommands() {
+	if app.commands != nil {
+		delete(app.commands, CompletionCommandName)
+		delete(app.commands, completeCommandName)
+	}
+
+	app.registerCommand(completionCommand(app))
+	app.registerCommand(completeCommand(app))
+}
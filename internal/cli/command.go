@@ -17,12 +17,34 @@ type Command struct {
 	Description          string
 	Aliases              []string
 	Arguments            []Argument
+	Flags                []Flag
 	Executor             CommandExecutor
 	LogToFile            bool
 	MustBeRunFromSession bool
+	// Hidden commands are not listed in the help output.
+	Hidden bool
+	// When enabled, arguments are passed to the executor as is, without
+	// parsing flags or enforcing required arguments.
+	RawArguments bool
 }
 
 func (command Command) Execute(ctx context.Context, args []string) error {
+	if !command.RawArguments {
+		flags, positional, err := parseFlags(command.Flags, args)
+		if err != nil {
+			return err
+		}
+
+		for i, argument := range command.Arguments {
+			if argument.Required && i >= len(positional) {
+				return fmt.Errorf("%w '%s'", ErrMissingArgument, argument.Name)
+			}
+		}
+
+		ctx = contextWithFlags(ctx, flags)
+		args = positional
+	}
+
 	if command.MustBeRunFromSession {
 		mgr, err := manager.GetManager(ctx)
 		if err != nil {
@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+var (
+	ErrUnknownFlag      = errors.New("unknown flag")
+	ErrMissingFlagValue = errors.New("missing value for flag")
+	ErrInvalidFlagValue = errors.New("invalid value for flag")
+	ErrMissingArgument  = errors.New("missing required argument")
+)
+
+type FlagType int
+
+const (
+	FlagTypeBool FlagType = iota
+	FlagTypeString
+	FlagTypeDuration
+	FlagTypeInt
+	// A string flag that can be provided multiple times.
+	FlagTypeStringSlice
+)
+
+func (t FlagType) String() string {
+	return [...]string{"bool", "string", "duration", "int", "string..."}[t]
+}
+
+type Flag struct {
+	// The long form of the flag, used as --name.
+	Name string
+	// The optional short form of the flag, used as -s.
+	Short       string
+	Description string
+	Type        FlagType
+	// The default value for the flag, formatted as it would be on the
+	// command line.
+	Default string
+	// The placeholder for the flags value displayed in help output.
+	ValueName string
+}
+
+func (f Flag) Label() string {
+	label := color.HiWhiteString("--%s", f.Name)
+	if f.Short != "" {
+		label = fmt.Sprintf("%s, %s", color.HiWhiteString("-%s", f.Short), label)
+	}
+
+	if f.Type != FlagTypeBool {
+		valueName := f.ValueName
+		if valueName == "" {
+			valueName = f.Type.String()
+		}
+
+		label = fmt.Sprintf("%s <%s>", label, valueName)
+	}
+
+	if f.Type == FlagTypeStringSlice {
+		label = fmt.Sprintf("%s %s", label, color.BlueString("(repeatable)"))
+	}
+
+	if f.Default != "" {
+		label = fmt.Sprintf("%s %s", label, color.BlueString("(default: %s)", f.Default))
+	}
+
+	return label
+}
+
+func (f Flag) parse(value string) (any, error) {
+	switch f.Type {
+	case FlagTypeBool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w --%s: %s", ErrInvalidFlagValue, f.Name, value)
+		}
+		return v, nil
+	case FlagTypeDuration:
+		v, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w --%s: %s", ErrInvalidFlagValue, f.Name, value)
+		}
+		return v, nil
+	case FlagTypeInt:
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w --%s: %s", ErrInvalidFlagValue, f.Name, value)
+		}
+		return v, nil
+	case FlagTypeStringSlice:
+		return []string{value}, nil
+	default:
+		return value, nil
+	}
+}
+
+// FlagValues holds the values of the flags parsed for a command invocation.
+type FlagValues struct {
+	values map[string]any
+	set    map[string]bool
+}
+
+// IsSet returns true if the flag was explicitly provided on the command line.
+func (f FlagValues) IsSet(name string) bool {
+	return f.set[name]
+}
+
+func (f FlagValues) Bool(name string) bool {
+	v, _ := f.values[name].(bool)
+	return v
+}
+
+func (f FlagValues) String(name string) string {
+	v, _ := f.values[name].(string)
+	return v
+}
+
+func (f FlagValues) Duration(name string) time.Duration {
+	v, _ := f.values[name].(time.Duration)
+	return v
+}
+
+func (f FlagValues) Int(name string) int {
+	v, _ := f.values[name].(int)
+	return v
+}
+
+func (f FlagValues) StringSlice(name string) []string {
+	v, _ := f.values[name].([]string)
+	return v
+}
+
+type flagsCtxKey struct{}
+
+func contextWithFlags(ctx context.Context, flags FlagValues) context.Context {
+	return context.WithValue(ctx, flagsCtxKey{}, flags)
+}
+
+// FlagsFromContext returns the flags parsed for the command being executed.
+func FlagsFromContext(ctx context.Context) FlagValues {
+	if flags, ok := ctx.Value(flagsCtxKey{}).(FlagValues); ok {
+		return flags
+	}
+
+	return FlagValues{values: map[string]any{}, set: map[string]bool{}}
+}
+
+// parseFlags separates the flags from the positional arguments. Flags may
+// appear anywhere in args, in the forms --name value, --name=value, -s value
+// and -s=value. Boolean flags do not take a value unless provided using =.
+// Everything following "--" is treated as a positional argument.
+func parseFlags(flags []Flag, args []string) (FlagValues, []string, error) {
+	result := FlagValues{values: map[string]any{}, set: map[string]bool{}}
+	positional := []string{}
+
+	for _, flag := range flags {
+		if flag.Default == "" {
+			continue
+		}
+
+		value, err := flag.parse(flag.Default)
+		if err != nil {
+			return result, nil, err
+		}
+		result.values[flag.Name] = value
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+
+		flag, ok := lookupFlag(flags, name, !strings.HasPrefix(arg, "--"))
+		if !ok {
+			return result, nil, fmt.Errorf("%w: %s", ErrUnknownFlag, arg)
+		}
+
+		if !hasValue {
+			if flag.Type == FlagTypeBool {
+				value = "true"
+			} else {
+				if i+1 >= len(args) {
+					return result, nil, fmt.Errorf("%w --%s", ErrMissingFlagValue, flag.Name)
+				}
+
+				i++
+				value = args[i]
+			}
+		}
+
+		parsed, err := flag.parse(value)
+		if err != nil {
+			return result, nil, err
+		}
+
+		// Repeated flags accumulate, replacing the default on first use.
+		if flag.Type == FlagTypeStringSlice && result.set[flag.Name] {
+			parsed = append(result.StringSlice(flag.Name), parsed.([]string)...)
+		}
+
+		result.values[flag.Name] = parsed
+		result.set[flag.Name] = true
+	}
+
+	return result, positional, nil
+}
+
+func lookupFlag(flags []Flag, name string, short bool) (Flag, bool) {
+	for _, flag := range flags {
+		if short && flag.Short != "" && flag.Short == name {
+			return flag, true
+		}
+
+		if !short && flag.Name == name {
+			return flag, true
+		}
+	}
+
+	return Flag{}, false
+}
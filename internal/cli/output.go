@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+)
+
+const (
+	OutputFlagName string = "output"
+	FormatFlagName string = "format"
+)
+
+var (
+	ErrInvalidOutputMode = errors.New("invalid output mode")
+)
+
+type OutputMode string
+
+const (
+	// Human readable lines. This is the default.
+	OutputModePlain OutputMode = "plain"
+	// Aligned columns with a header row.
+	OutputModeTable OutputMode = "table"
+	// Indented JSON.
+	OutputModeJSON OutputMode = "json"
+)
+
+// OutputFlags returns the flags used to select how the data produced by a
+// command is written. They are intended to be used as global flags.
+func OutputFlags() []Flag {
+	return []Flag{
+		{
+			Name:        OutputFlagName,
+			Short:       "o",
+			Description: "How to write the output. Can be one of plain, table or json.",
+			Type:        FlagTypeString,
+			Default:     string(OutputModePlain),
+			ValueName:   "mode",
+		},
+		{
+			Name:        FormatFlagName,
+			Description: "A Go template used to write the output, executed once for each item.",
+			Type:        FlagTypeString,
+			ValueName:   "template",
+		},
+	}
+}
+
+// Output is the data produced by a command, along with the representations
+// used for each output mode.
+type Output struct {
+	// The value encoded for json output and passed to format templates. When
+	// Data is a slice, format templates are executed once for each element.
+	Data any
+	// The column headers used for table output.
+	Header []string
+	// The rows used for table output.
+	Rows [][]string
+	// The lines used for plain output.
+	Lines []string
+}
+
+// WriteOutput writes the output to stdout using the output mode selected on
+// the command line.
+func WriteOutput(ctx context.Context, output Output) error {
+	return output.Write(ctx, os.Stdout)
+}
+
+func (o Output) Write(ctx context.Context, out io.Writer) error {
+	flags := FlagsFromContext(ctx)
+
+	if format := flags.String(FormatFlagName); format != "" {
+		return o.writeFormat(out, format)
+	}
+
+	switch OutputMode(flags.String(OutputFlagName)) {
+	case "", OutputModePlain:
+		for _, line := range o.Lines {
+			if _, err := fmt.Fprintln(out, line); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case OutputModeTable:
+		return o.writeTable(out)
+	case OutputModeJSON:
+		data, err := json.MarshalIndent(o.Data, "", "    ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	}
+
+	return fmt.Errorf("%w: %s", ErrInvalidOutputMode, flags.String(OutputFlagName))
+}
+
+func (o Output) writeTable(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	if len(o.Header) > 0 {
+		fmt.Fprintln(w, strings.Join(o.Header, "\t"))
+	}
+
+	for _, row := range o.Rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	return w.Flush()
+}
+
+func (o Output) writeFormat(out io.Writer, format string) error {
+	tmpl, err := template.New("format").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(format)
+	if err != nil {
+		return fmt.Errorf("invalid format: %v", err)
+	}
+
+	items := []any{o.Data}
+	if value := reflect.ValueOf(o.Data); value.Kind() == reflect.Slice {
+		items = make([]any, value.Len())
+		for i := range items {
+			items[i] = value.Index(i).Interface()
+		}
+	}
+
+	for _, item := range items {
+		if err := tmpl.Execute(out, item); err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintln(out); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"slices"
 	"sort"
 	"strings"
 	"text/template"
@@ -24,12 +25,22 @@ func defaultTemplateFuncs(app CliApp) template.FuncMap {
 		"commands": func() []Command {
 			return commands(app)
 		},
+		"commandNames": func() string {
+			return strings.Join(app.commandNames(), " ")
+		},
+		"programNames": func() string {
+			return strings.Join(programNames(app), " ")
+		},
 	}
 }
 
 func commands(app CliApp) []Command {
 	commands := make([]Command, 0, len(app.commands))
 	for _, cmd := range app.commands {
+		if cmd.Hidden {
+			continue
+		}
+
 		commands = append(commands, cmd)
 	}
 
@@ -96,3 +107,15 @@ func white(s string) string {
 func whiteCommand(cmd string) string {
 	return color.HiWhiteString(cmd)
 }
+
+// programNames returns the names the completion scripts are registered for.
+func programNames(app CliApp) []string {
+	names := []string{app.Name}
+	for _, alias := range app.Config.CompletionAliases {
+		if !slices.Contains(names, alias) {
+			names = append(names, alias)
+		}
+	}
+
+	return names
+}
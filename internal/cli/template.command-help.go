@@ -10,7 +10,7 @@ Description
 
 Usage
 
-    {{white getCallerName}} {{whiteCommand .Name}} {{range .Arguments}}{{if .Required}}<{{.Name}}>{{else}}[{{.Name}}]{{end}} {{end}}
+    {{white getCallerName}} {{whiteCommand .Name}} {{range .Arguments}}{{if .Required}}<{{.Name}}>{{else}}[{{.Name}}]{{end}} {{end}}{{if .Flags}}[flags]{{end}}
 {{if .Arguments}}
 
 Arguments
@@ -21,6 +21,15 @@ Arguments
     {{$argDesc}}
 {{end}}
 {{end}}
+{{- if .Flags}}
+Flags
+{{range .Flags}}
+    {{- $flagDesc := wrap .Description getMaxWidth 4}}
+    {{.Label}}
+
+    {{$flagDesc}}
+{{end}}
+{{end}}
 Run '{{getCallerName}} help' for information on additional commands.
 
 `
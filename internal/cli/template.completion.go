@@ -0,0 +1,52 @@
+package cli
+
+var bashCompletionTemplate string = `# bash completion for {{getCallerName}}
+
+_{{getCallerName}}_completions() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+
+    if [[ ${COMP_CWORD} -eq 1 ]]; then
+        COMPREPLY=($(compgen -W "{{commandNames}}" -- "${cur}"))
+        return
+    fi
+
+    local IFS=$'\n'
+    COMPREPLY=($({{getCallerName}} __complete "${COMP_WORDS[@]:1:${COMP_CWORD}}" 2>/dev/null))
+}
+
+complete -o default -F _{{getCallerName}}_completions {{programNames}}
+`
+
+var zshCompletionTemplate string = `#compdef {{programNames}}
+
+_{{getCallerName}}() {
+    if (( CURRENT == 2 )); then
+        compadd -- {{commandNames}}
+        return
+    fi
+
+    local -a candidates
+    candidates=("${(@f)$({{getCallerName}} __complete "${(@)words[2,${CURRENT}]}" 2>/dev/null)}")
+
+    if [[ -n "${candidates[1]}" ]]; then
+        compadd -- "${candidates[@]}"
+    else
+        _files
+    fi
+}
+
+compdef _{{getCallerName}} {{programNames}}
+`
+
+var fishCompletionTemplate string = `# fish completion for {{getCallerName}}
+
+function __{{getCallerName}}_complete
+    set -l tokens (commandline -opc) (commandline -ct)
+    {{getCallerName}} __complete $tokens[2..-1] 2>/dev/null
+end
+
+for name in {{programNames}}
+    complete -c $name -f -n "__fish_use_subcommand" -a "{{commandNames}}"
+    complete -c $name -n "not __fish_use_subcommand" -a "(__{{getCallerName}}_complete)"
+end
+`
@@ -2,10 +2,103 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/letstrygo/letstry/internal/config/editors"
 )
 
+const (
+	DefaultTrashMaxAge  = 7 * 24 * time.Hour
+	DefaultTrashMaxSize = 1024 * 1024 * 1024
+	DefaultCacheMaxSize = 2 * 1024 * 1024 * 1024
+)
+
+type TrashConfig struct {
+	// How long deleted sessions and templates are kept in the trash.
+	// (Default: 7 days)
+	MaxAge time.Duration `json:"max_age"`
+	// The maximum combined size of the trash in bytes. Once exceeded, the
+	// oldest entries are removed first. (Default: 1 GiB)
+	MaxSize int64 `json:"max_size"`
+}
+
+// GetMaxAge returns the configured maximum age, or the default if unset.
+func (t TrashConfig) GetMaxAge() time.Duration {
+	if t.MaxAge <= 0 {
+		return DefaultTrashMaxAge
+	}
+
+	return t.MaxAge
+}
+
+// GetMaxSize returns the configured maximum size, or the default if unset.
+func (t TrashConfig) GetMaxSize() int64 {
+	if t.MaxSize <= 0 {
+		return DefaultTrashMaxSize
+	}
+
+	return t.MaxSize
+}
+
+type CacheConfig struct {
+	// When enabled, repositories are always cloned from their remote rather
+	// than from a local mirror.
+	Disabled bool `json:"disabled"`
+	// The maximum combined size of the repository mirrors in bytes. Once
+	// exceeded, the least recently used mirrors are removed first.
+	// (Default: 2 GiB)
+	MaxSize int64 `json:"max_size"`
+}
+
+// GetMaxSize returns the configured maximum size, or the default if unset.
+func (c CacheConfig) GetMaxSize() int64 {
+	if c.MaxSize <= 0 {
+		return DefaultCacheMaxSize
+	}
+
+	return c.MaxSize
+}
+
+type IgnoreConfig struct {
+	// Patterns, using gitignore syntax, for files that are never copied when
+	// saving or exporting a session, or when creating a session from a
+	// directory. They are applied before any ignore files.
+	Patterns []string `json:"patterns"`
+	// When enabled, .gitignore files are honored as well as .letstryignore
+	// files.
+	UseGitignore bool `json:"use_gitignore"`
+}
+
+// SourceAlias replaces a session source that exactly matches Name with
+// Source.
+type SourceAlias struct {
+	// The value that is replaced, for example "api".
+	Name string `json:"name"`
+	// The source used in place of Name. Rewrite rules are applied to it.
+	Source string `json:"source"`
+	// The branch, tag or commit SHA to check out when Source is a git
+	// repository.
+	Ref string `json:"ref,omitempty"`
+	// The directory of the repository to use when Source is a git
+	// repository.
+	Subdir string `json:"subdir,omitempty"`
+}
+
+// SourceRewrite rewrites session sources that start with Prefix. The
+// remainder of the source replaces "{path}" in Source, for example the
+// prefix "gh:" with the source "https://github.com/{path}.git".
+type SourceRewrite struct {
+	Prefix string `json:"prefix"`
+	Source string `json:"source"`
+}
+
+type SourcesConfig struct {
+	// Shorthand names for specific sources.
+	Aliases []SourceAlias `json:"aliases"`
+	// Prefixes that are expanded into sources.
+	Rewrites []SourceRewrite `json:"rewrites"`
+}
+
 type Config struct {
 	path string
 
@@ -21,6 +114,15 @@ type Config struct {
 	DefaultEditorName editors.EditorName `json:"default_editor"`
 	// Editors available for use within LetsTry. (Default: vscode)
 	AvailableEditors []editors.Editor `json:"editors"`
+	// Retention for deleted sessions and templates, which are moved to the
+	// trash rather than being deleted immediately.
+	Trash TrashConfig `json:"trash"`
+	// Rules for files that should not be copied.
+	Ignore IgnoreConfig `json:"ignore"`
+	// Local mirrors of the git repositories used as sources.
+	Cache CacheConfig `json:"cache"`
+	// Aliases and rewrite rules for session sources.
+	Sources SourcesConfig `json:"sources"`
 }
 
 func (cfg Config) Path() string {
@@ -0,0 +1,237 @@
+package editors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"text/template"
+)
+
+var (
+	ErrInvalidArguments = errors.New("invalid editor arguments")
+)
+
+// Arguments are the arguments passed to an editor. They are split into words
+// using shell quoting rules, so arguments containing spaces can be quoted.
+// In the config file they can also be written as a JSON array, in which case
+// each element is a single argument.
+//
+// Each argument may use the placeholders {{.Path}}, {{.SessionID}} and
+// {{.Source}}. When {{.Path}} is not used, the path of the session is passed
+// as the last argument.
+type Arguments string
+
+func (a Arguments) String() string {
+	return string(a)
+}
+
+func (a *Arguments) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*a = Arguments(value)
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("%w: must be a string or an array of strings", ErrInvalidArguments)
+	}
+
+	*a = ArgumentsFromList(list)
+	return nil
+}
+
+// ArgumentsFromList returns the arguments with each element of list quoted
+// as a single word.
+func ArgumentsFromList(list []string) Arguments {
+	words := make([]string, 0, len(list))
+	for _, arg := range list {
+		words = append(words, quoteWord(arg))
+	}
+
+	return Arguments(strings.Join(words, " "))
+}
+
+// Words splits the arguments into words. Backslashes are not treated as
+// escapes on Windows, where they separate the elements of a path.
+func (a Arguments) Words() ([]string, error) {
+	return splitWords(string(a), runtime.GOOS != "windows")
+}
+
+// LaunchContext describes the session an editor is launched for. It provides
+// the values of the placeholders used in the editors arguments.
+type LaunchContext struct {
+	// The directory of the session.
+	Path string
+	// The ID of the session.
+	SessionID string
+	// The source the session was created from.
+	Source string
+}
+
+// placeholders exposes the launch context to the argument templates, and
+// records whether the path was used.
+type placeholders struct {
+	ctx      LaunchContext
+	usedPath bool
+}
+
+func (p *placeholders) Path() string {
+	p.usedPath = true
+	return p.ctx.Path
+}
+
+func (p *placeholders) SessionID() string {
+	return p.ctx.SessionID
+}
+
+func (p *placeholders) Source() string {
+	return p.ctx.Source
+}
+
+// Render returns the arguments for the launch context, with the placeholders
+// in each argument replaced.
+func (a Arguments) Render(ctx LaunchContext) ([]string, error) {
+	words, err := a.Words()
+	if err != nil {
+		return nil, err
+	}
+
+	data := &placeholders{ctx: ctx}
+
+	args := make([]string, 0, len(words)+1)
+	for _, word := range words {
+		if !strings.Contains(word, "{{") {
+			args = append(args, word)
+			continue
+		}
+
+		tmpl, err := template.New("args").Option("missingkey=error").Parse(word)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArguments, err)
+		}
+
+		var out bytes.Buffer
+		if err := tmpl.Execute(&out, data); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArguments, err)
+		}
+
+		args = append(args, out.String())
+	}
+
+	if !data.usedPath {
+		args = append(args, ctx.Path)
+	}
+
+	return args, nil
+}
+
+// splitWords splits value into words the way a POSIX shell would, honoring
+// single quotes, double quotes and, when escapes is set, backslash escapes.
+// Placeholders are kept intact, even when they contain spaces or quotes. No
+// expansion is performed.
+func splitWords(value string, escapes bool) ([]string, error) {
+	var (
+		words   []string
+		current strings.Builder
+		inWord  bool
+		quote   byte
+		escaped bool
+	)
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+
+		switch {
+		case escaped:
+			// Within double quotes, a backslash only escapes characters
+			// that are special within them.
+			if quote == '"' && !strings.ContainsRune("\"\\$`", rune(c)) {
+				current.WriteByte('\\')
+			}
+			current.WriteByte(c)
+			escaped = false
+		case strings.HasPrefix(value[i:], "{{"):
+			end := strings.Index(value[i:], "}}")
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated placeholder in %s", ErrInvalidArguments, value)
+			}
+
+			current.WriteString(value[i : i+end+2])
+			i += end + 1
+			inWord = true
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				current.WriteByte(c)
+			}
+		case c == '\\' && escapes:
+			escaped = true
+			inWord = true
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else {
+				current.WriteByte(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteByte(c)
+			inWord = true
+		}
+	}
+
+	if escaped {
+		return nil, fmt.Errorf("%w: trailing backslash in %s", ErrInvalidArguments, value)
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("%w: unterminated %c quote in %s", ErrInvalidArguments, quote, value)
+	}
+
+	if inWord {
+		words = append(words, current.String())
+	}
+
+	return words, nil
+}
+
+// quoteWord quotes value so that splitWords returns it as a single word,
+// whether or not backslash escapes are used. Placeholders are left as they
+// are.
+func quoteWord(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n'\"\\") {
+		return value
+	}
+
+	var quoted strings.Builder
+	quoted.WriteByte('\'')
+
+	for i := 0; i < len(value); i++ {
+		switch {
+		case strings.HasPrefix(value[i:], "{{") && strings.Contains(value[i:], "}}"):
+			end := strings.Index(value[i:], "}}")
+			quoted.WriteString(value[i : i+end+2])
+			i += end + 1
+		case value[i] == '\'':
+			quoted.WriteString(`'"'"'`)
+		default:
+			quoted.WriteByte(value[i])
+		}
+	}
+
+	quoted.WriteByte('\'')
+	return quoted.String()
+}
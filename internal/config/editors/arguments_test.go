@@ -0,0 +1,107 @@
+package editors
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		escapes bool
+		want    []string
+	}{
+		{name: "empty", value: "", escapes: true, want: nil},
+		{name: "whitespace", value: " \t\n ", escapes: true, want: nil},
+		{name: "words", value: "-n  --wait\t-g", escapes: true, want: []string{"-n", "--wait", "-g"}},
+		{name: "single quotes", value: `'a b' 'c\d'`, escapes: true, want: []string{"a b", `c\d`}},
+		{name: "double quotes", value: `"a b" "c\"d" "e\f"`, escapes: true, want: []string{"a b", `c"d`, `e\f`}},
+		{name: "empty quotes", value: `'' ""`, escapes: true, want: []string{"", ""}},
+		{name: "adjacent quotes", value: `a'b c'"d"`, escapes: true, want: []string{"ab cd"}},
+		{name: "escaped space", value: `a\ b`, escapes: true, want: []string{"a b"}},
+		{name: "placeholder", value: "--folder-uri file://{{.Path}}", escapes: true, want: []string{"--folder-uri", "file://{{.Path}}"}},
+		{name: "placeholder with spaces", value: "--goto {{ .Path }}", escapes: true, want: []string{"--goto", "{{ .Path }}"}},
+		{name: "placeholder with quotes", value: `{{ printf "%s:1" .Path }} -n`, escapes: true, want: []string{`{{ printf "%s:1" .Path }}`, "-n"}},
+		{name: "quoted placeholder", value: `'{{ printf "%s" .Path }}'`, escapes: true, want: []string{`{{ printf "%s" .Path }}`}},
+		{name: "windows path", value: `C:\Users\me\code "C:\Program Files\x"`, escapes: false, want: []string{`C:\Users\me\code`, `C:\Program Files\x`}},
+		{name: "windows trailing backslash", value: `C:\code\`, escapes: false, want: []string{`C:\code\`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitWords(tt.value, tt.escapes)
+			if err != nil {
+				t.Fatalf("splitWords(%q) error = %v", tt.value, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitWords(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitWordsInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "unterminated single quote", value: "'a b"},
+		{name: "unterminated double quote", value: `"a b`},
+		{name: "trailing backslash", value: `a\`},
+		{name: "unterminated placeholder", value: "{{ .Path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := splitWords(tt.value, true)
+			if !errors.Is(err, ErrInvalidArguments) {
+				t.Errorf("splitWords(%q) error = %v, want %v", tt.value, err, ErrInvalidArguments)
+			}
+		})
+	}
+}
+
+func TestArgumentsFromList(t *testing.T) {
+	list := []string{"a b", "it's", `C:\code`, "", `{{ printf "'%s'" .Path }}`}
+
+	for _, escapes := range []bool{true, false} {
+		got, err := splitWords(ArgumentsFromList(list).String(), escapes)
+		if err != nil {
+			t.Fatalf("splitWords() error = %v", err)
+		}
+
+		if !reflect.DeepEqual(got, list) {
+			t.Errorf("splitWords(escapes=%v) = %q, want %q", escapes, got, list)
+		}
+	}
+}
+
+func TestArgumentsRender(t *testing.T) {
+	ctx := LaunchContext{Path: "/tmp/my session", SessionID: "abc", Source: "tpl"}
+
+	tests := []struct {
+		name string
+		args Arguments
+		want []string
+	}{
+		{name: "appends path", args: "-n", want: []string{"-n", "/tmp/my session"}},
+		{name: "placeholder with spaces", args: "--goto {{ .Path }} --id={{ .SessionID }}", want: []string{"--goto", "/tmp/my session", "--id=abc"}},
+		{name: "placeholder in quotes", args: "'file://{{.Path}}' {{.Source}}", want: []string{"file:///tmp/my session", "tpl"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.args.Render(ctx)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
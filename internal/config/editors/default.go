@@ -15,5 +15,8 @@ func DefaultEditors() []Editor {
 		}
 	}
 
+	// Editors with a preset are added when they can be found.
+	result = append(result, DetectEditors()...)
+
 	return result
 }
@@ -0,0 +1,21 @@
+package editors
+
+import "time"
+
+const (
+	EditorNameCursor EditorName = "cursor"
+)
+
+func CursorEditorPreset() Preset {
+	return Preset{
+		Editor: Editor{
+			Name:                EditorNameCursor,
+			Args:                "-n",
+			ProcessCaptureDelay: time.Second * 5,
+			TrackingType:        TrackingTypeProcess,
+			PIDResolution:       PIDResolutionLocation,
+			ProcessName:         "cursor",
+		},
+		Commands: []string{"cursor"},
+	}
+}
@@ -0,0 +1,17 @@
+package editors
+
+const (
+	EditorNameEmacs EditorName = "emacs"
+)
+
+func EmacsEditorPreset() Preset {
+	return Preset{
+		// Emacs runs until its frame is closed.
+		Editor: Editor{
+			Name:         EditorNameEmacs,
+			TrackingType: TrackingTypeWait,
+		},
+		Commands:   []string{"emacs"},
+		FlatpakIDs: []string{"org.gnu.emacs"},
+	}
+}
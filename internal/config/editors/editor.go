@@ -1,8 +1,10 @@
 package editors
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"time"
 
@@ -24,11 +26,16 @@ func (t TrackingType) String() string {
 const (
 	TrackingTypeFileAccess TrackingType = "file_access"
 	TrackingTypeProcess    TrackingType = "process"
+	// letstry waits for the launched editor process to exit, and removes
+	// the session as soon as it does. This suits terminal editors and
+	// editors that block until they are closed, such as `code --wait`.
+	TrackingTypeWait TrackingType = "wait"
 )
 
 var AllTrackingTypes = []TrackingType{
 	TrackingTypeFileAccess,
 	TrackingTypeProcess,
+	TrackingTypeWait,
 }
 
 func GetTrackingType(value string) (TrackingType, error) {
@@ -41,15 +48,168 @@ func GetTrackingType(value string) (TrackingType, error) {
 	return "", fmt.Errorf("unknown tracking type: %s", value)
 }
 
+type RunType string
+
+func (t RunType) String() string {
+	return string(t)
+}
+
+const (
+	// Spawn the editor directly. This is the default.
+	RunTypeRun RunType = "run"
+	// Run the editor through the users shell, so that aliases and the
+	// shells PATH can be used.
+	RunTypeShell RunType = "shell"
+	// Open the session directory using the systems default application,
+	// such as xdg-open. The path of the editor is not used.
+	RunTypeOpen RunType = "open"
+	// Run the editor in the current terminal. Foreground editors always use
+	// the wait tracking type.
+	RunTypeForeground RunType = "foreground"
+)
+
+var AllRunTypes = []RunType{
+	RunTypeRun,
+	RunTypeShell,
+	RunTypeOpen,
+	RunTypeForeground,
+}
+
+func GetRunType(value string) (RunType, error) {
+	for _, t := range AllRunTypes {
+		if t.String() == value {
+			return t, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown run type: %s", value)
+}
+
+type PIDResolution string
+
+func (r PIDResolution) String() string {
+	return string(r)
+}
+
+const (
+	// Use the PID of the process launched by letstry.
+	PIDResolutionLaunched PIDResolution = "launched"
+	// Use the newest process in the child-process tree of the launched process.
+	PIDResolutionTree PIDResolution = "tree"
+	// Use the oldest process whose command line or working directory
+	// references the session location, searching the child-process tree of
+	// the launched process before all other processes. This is the default.
+	PIDResolutionLocation PIDResolution = "location"
+)
+
+var AllPIDResolutions = []PIDResolution{
+	PIDResolutionLaunched,
+	PIDResolutionTree,
+	PIDResolutionLocation,
+}
+
+func GetPIDResolution(value string) (PIDResolution, error) {
+	for _, r := range AllPIDResolutions {
+		if r.String() == value {
+			return r, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown pid resolution: %s", value)
+}
+
 type Editor struct {
-	Name                EditorName    `json:"name"`
+	Name EditorName `json:"name"`
+	// How the editor is launched. (Default: run)
+	RunType             RunType       `json:"run_type,omitempty"`
 	ExecPath            string        `json:"path"`
-	Args                string        `json:"args"`
+	Args                Arguments     `json:"args"`
 	ProcessCaptureDelay time.Duration `json:"process_capture_delay"`
 	TrackingType        TrackingType  `json:"tracking_type"`
+	// How the PID of the editor is located when using the process tracking
+	// type. (Default: location)
+	PIDResolution PIDResolution `json:"pid_resolution,omitempty"`
+	// When set, only processes whose name contains this value are considered
+	// when locating the PID of the editor.
+	ProcessName string `json:"process_name,omitempty"`
+}
+
+// RunTypeOrDefault returns the run type for the editor, falling back to the
+// default when none has been configured.
+func (e Editor) RunTypeOrDefault() RunType {
+	if e.RunType == "" {
+		return RunTypeRun
+	}
+
+	return e.RunType
+}
+
+// TrackingTypeOrDefault returns the tracking type for the editor. Editors
+// run in the foreground own the terminal until they exit, so they are always
+// waited on.
+func (e Editor) TrackingTypeOrDefault() TrackingType {
+	if e.RunTypeOrDefault() == RunTypeForeground {
+		return TrackingTypeWait
+	}
+
+	return e.TrackingType
+}
+
+// Validate returns an error if the editor is not configured correctly.
+func (e Editor) Validate() error {
+	if e.Name == "" {
+		return errors.New("editor name is required")
+	}
+
+	if _, err := GetRunType(e.RunTypeOrDefault().String()); err != nil {
+		return err
+	}
+
+	if _, err := GetTrackingType(e.TrackingTypeOrDefault().String()); err != nil {
+		return err
+	}
+
+	if e.PIDResolution != "" {
+		if _, err := GetPIDResolution(e.PIDResolution.String()); err != nil {
+			return err
+		}
+	}
+
+	// The process launched to open the directory exits immediately.
+	if e.RunTypeOrDefault() == RunTypeOpen && e.TrackingType == TrackingTypeWait {
+		return fmt.Errorf("the %s tracking type can not be used with the %s run type", TrackingTypeWait, RunTypeOpen)
+	}
+
+	if _, err := e.Args.Words(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PIDResolutionOrDefault returns the PID resolution for the editor, falling back
+// to the default when none has been configured.
+func (e Editor) PIDResolutionOrDefault() PIDResolution {
+	// The process launched for the open run type exits once it has handed
+	// the directory to the default application, so only the location can
+	// be used to find the editor.
+	if e.PIDResolution == "" || e.RunTypeOrDefault() == RunTypeOpen {
+		return PIDResolutionLocation
+	}
+
+	return e.PIDResolution
 }
 
 func (e Editor) IsInstalled() bool {
+	switch e.RunTypeOrDefault() {
+	case RunTypeOpen:
+		_, err := exec.LookPath(openCommand())
+		return err == nil
+	case RunTypeShell:
+		// The shell may resolve the editor using an alias or its PATH.
+		return true
+	}
+
 	_, err := os.Stat(e.ExecPath)
 	return err == nil
 }
@@ -59,7 +219,7 @@ func (e Editor) GetExecName() string {
 }
 
 func (e Editor) FullString() string {
-	return fmt.Sprintf("name: %s, location: %s, args: %s", color.BlueString(e.Name.String()), color.YellowString(e.ExecPath), color.GreenString(e.Args))
+	return fmt.Sprintf("name: %s, location: %s, args: %s", color.BlueString(e.Name.String()), color.YellowString(e.ExecPath), color.GreenString(e.Args.String()))
 }
 
 func (e Editor) String() string {
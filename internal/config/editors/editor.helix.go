@@ -0,0 +1,18 @@
+package editors
+
+const (
+	EditorNameHelix EditorName = "helix"
+)
+
+func HelixEditorPreset() Preset {
+	return Preset{
+		Editor: Editor{
+			Name:         EditorNameHelix,
+			RunType:      RunTypeForeground,
+			TrackingType: TrackingTypeWait,
+		},
+		// Some distributions install helix as "helix" rather than "hx".
+		Commands:   []string{"hx", "helix"},
+		FlatpakIDs: []string{"com.helix_editor.Helix"},
+	}
+}
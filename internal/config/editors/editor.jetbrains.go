@@ -0,0 +1,45 @@
+package editors
+
+import "time"
+
+const (
+	EditorNameGoLand   EditorName = "goland"
+	EditorNameIntelliJ EditorName = "intellij"
+)
+
+// jetBrainsEditor returns an editor for a JetBrains IDE. The IDEs launcher
+// exits once the project has been handed to the IDE, so the IDE process is
+// located using the session directory. The process name is not used, as the
+// IDE runs as java when started using the launcher scripts.
+func jetBrainsEditor(name EditorName) Editor {
+	return Editor{
+		Name:                name,
+		ProcessCaptureDelay: time.Second * 10,
+		TrackingType:        TrackingTypeProcess,
+		PIDResolution:       PIDResolutionLocation,
+	}
+}
+
+func GoLandEditorPreset() Preset {
+	return Preset{
+		Editor:     jetBrainsEditor(EditorNameGoLand),
+		Commands:   []string{"goland", "goland.sh"},
+		FlatpakIDs: []string{"com.jetbrains.GoLand"},
+	}
+}
+
+func IntelliJEditorPreset() Preset {
+	return Preset{
+		Editor: jetBrainsEditor(EditorNameIntelliJ),
+		Commands: []string{
+			"idea",
+			"idea.sh",
+			"intellij-idea-ultimate",
+			"intellij-idea-community",
+		},
+		FlatpakIDs: []string{
+			"com.jetbrains.IntelliJ-IDEA-Ultimate",
+			"com.jetbrains.IntelliJ-IDEA-Community",
+		},
+	}
+}
@@ -0,0 +1,17 @@
+package editors
+
+const (
+	EditorNameNeovim EditorName = "neovim"
+)
+
+func NeovimEditorPreset() Preset {
+	return Preset{
+		Editor: Editor{
+			Name:         EditorNameNeovim,
+			RunType:      RunTypeForeground,
+			TrackingType: TrackingTypeWait,
+		},
+		Commands:   []string{"nvim"},
+		FlatpakIDs: []string{"io.neovim.nvim"},
+	}
+}
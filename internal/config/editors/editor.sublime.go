@@ -0,0 +1,18 @@
+package editors
+
+const (
+	EditorNameSublimeText EditorName = "sublime"
+)
+
+func SublimeTextEditorPreset() Preset {
+	return Preset{
+		// subl blocks until the window is closed when using --wait.
+		Editor: Editor{
+			Name:         EditorNameSublimeText,
+			Args:         "--new-window --wait",
+			TrackingType: TrackingTypeWait,
+		},
+		Commands:   []string{"subl", "sublime-text.subl", "sublime_text"},
+		FlatpakIDs: []string{"com.sublimetext.three"},
+	}
+}
@@ -39,5 +39,7 @@ func VSCodeEditor() Editor {
 		Args:                "-n",
 		ProcessCaptureDelay: time.Second * 5,
 		TrackingType:        trackingType,
+		PIDResolution:       PIDResolutionLocation,
+		ProcessName:         "code",
 	}
 }
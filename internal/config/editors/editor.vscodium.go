@@ -0,0 +1,22 @@
+package editors
+
+import "time"
+
+const (
+	EditorNameVSCodium EditorName = "vscodium"
+)
+
+func VSCodiumEditorPreset() Preset {
+	return Preset{
+		Editor: Editor{
+			Name:                EditorNameVSCodium,
+			Args:                "-n",
+			ProcessCaptureDelay: time.Second * 5,
+			TrackingType:        TrackingTypeProcess,
+			PIDResolution:       PIDResolutionLocation,
+			ProcessName:         "codium",
+		},
+		Commands:   []string{"codium"},
+		FlatpakIDs: []string{"com.vscodium.codium"},
+	}
+}
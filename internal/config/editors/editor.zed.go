@@ -0,0 +1,18 @@
+package editors
+
+const (
+	EditorNameZed EditorName = "zed"
+)
+
+func ZedEditorPreset() Preset {
+	return Preset{
+		// The Zed CLI blocks until the window is closed when using --wait.
+		Editor: Editor{
+			Name:         EditorNameZed,
+			Args:         "--new --wait",
+			TrackingType: TrackingTypeWait,
+		},
+		Commands:   []string{"zed", "zeditor", "zedit"},
+		FlatpakIDs: []string{"dev.zed.Zed"},
+	}
+}
@@ -0,0 +1,79 @@
+package editors
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Command returns the command used to open the session described by launch
+// in the editor, based on the editors run type. Commands for the foreground
+// run type are attached to the current terminal.
+func (e Editor) Command(launch LaunchContext) (*exec.Cmd, error) {
+	args, err := e.Args.Render(launch)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.RunTypeOrDefault() {
+	case RunTypeRun:
+		return exec.Command(e.ExecPath, args...), nil
+	case RunTypeShell:
+		return shellCommand(e.ExecPath, args), nil
+	case RunTypeOpen:
+		if runtime.GOOS == "windows" {
+			return exec.Command("cmd", "/C", "start", "", launch.Path), nil
+		}
+
+		return exec.Command(openCommand(), launch.Path), nil
+	case RunTypeForeground:
+		cmd := exec.Command(e.ExecPath, args...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd, nil
+	}
+
+	return nil, fmt.Errorf("unknown run type: %s", e.RunType)
+}
+
+// openCommand returns the command used to open a directory with the systems
+// default application.
+func openCommand() string {
+	if runtime.GOOS == "darwin" {
+		return "open"
+	}
+
+	return "xdg-open"
+}
+
+// shellCommand returns a command running name with args in the users login
+// shell, so that the PATH set up by the shells profile can be used. The
+// shell is not interactive, as it would try to take over the terminal.
+func shellCommand(name string, args []string) *exec.Cmd {
+	// The arguments are quoted for the program by exec, which cmd passes on
+	// unchanged.
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", append([]string{"/C", name}, args...)...)
+	}
+
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+
+	words := []string{name}
+	for _, arg := range args {
+		words = append(words, quoteShellWord(arg))
+	}
+
+	return exec.Command(shell, "-l", "-c", strings.Join(words, " "))
+}
+
+// quoteShellWord quotes value so that it is passed to a POSIX shell as a
+// single word.
+func quoteShellWord(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
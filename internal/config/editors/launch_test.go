@@ -0,0 +1,39 @@
+package editors
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestShellCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell editors are run through cmd on windows")
+	}
+
+	t.Setenv("SHELL", "/bin/sh")
+
+	editor := Editor{
+		Name:     "printf",
+		ExecPath: "printf",
+		Args:     `'[%s]' {{.Path}} {{.Source}}`,
+		RunType:  RunTypeShell,
+	}
+
+	cmd, err := editor.Command(LaunchContext{
+		Path:   `/tmp/it's "my" $(session)`,
+		Source: "a; echo pwned",
+	})
+	if err != nil {
+		t.Fatalf("Command() error = %v", err)
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("running %q: %v", cmd.Args, err)
+	}
+
+	want := `[/tmp/it's "my" $(session)][a; echo pwned]`
+	if string(out) != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
@@ -0,0 +1,96 @@
+package editors
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+var (
+	// The directory snap packages expose their commands in.
+	snapBinDirectory = filepath.Join("/", "snap", "bin")
+	// The directories flatpak applications are exported to, system wide and
+	// for the current user.
+	flatpakBinDirectories = []string{
+		filepath.Join("/", "var", "lib", "flatpak", "exports", "bin"),
+		filepath.Join("~", ".local", "share", "flatpak", "exports", "bin"),
+	}
+)
+
+// Preset is an editor letstry knows how to configure. The path of the editor
+// is discovered when the preset is detected.
+type Preset struct {
+	// The editor, without its path.
+	Editor Editor
+	// Names of the editors executable, looked up in PATH and in the snap bin
+	// directory.
+	Commands []string
+	// IDs of the editors flatpak application.
+	FlatpakIDs []string
+}
+
+// Presets returns every editor preset.
+func Presets() []Preset {
+	return []Preset{
+		VSCodiumEditorPreset(),
+		CursorEditorPreset(),
+		ZedEditorPreset(),
+		SublimeTextEditorPreset(),
+		GoLandEditorPreset(),
+		IntelliJEditorPreset(),
+		NeovimEditorPreset(),
+		HelixEditorPreset(),
+		EmacsEditorPreset(),
+	}
+}
+
+// Detect returns the editor with the path of the first installation found,
+// searching PATH, then snap and then flatpak.
+func (p Preset) Detect() (Editor, bool) {
+	for _, path := range p.candidates() {
+		if path, err := exec.LookPath(path); err == nil {
+			editor := p.Editor
+			editor.ExecPath = path
+			return editor, true
+		}
+	}
+
+	return Editor{}, false
+}
+
+func (p Preset) candidates() []string {
+	candidates := append([]string{}, p.Commands...)
+
+	for _, command := range p.Commands {
+		candidates = append(candidates, filepath.Join(snapBinDirectory, command))
+	}
+
+	home, _ := os.UserHomeDir()
+	for _, dir := range flatpakBinDirectories {
+		if len(dir) > 0 && dir[0] == '~' {
+			if home == "" {
+				continue
+			}
+			dir = filepath.Join(home, dir[1:])
+		}
+
+		for _, id := range p.FlatpakIDs {
+			candidates = append(candidates, filepath.Join(dir, id))
+		}
+	}
+
+	return candidates
+}
+
+// DetectEditors returns the editors of every preset that is installed.
+func DetectEditors() []Editor {
+	result := []Editor{}
+
+	for _, preset := range Presets() {
+		if editor, ok := preset.Detect(); ok {
+			result = append(result, editor)
+		}
+	}
+
+	return result
+}
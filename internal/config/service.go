@@ -48,22 +48,37 @@ func GetConfig() (*Config, error) {
 	return config, nil
 }
 
-func SaveConfig(cfg *Config) error {
+// UpdateConfig performs a locked read-modify-write of the config file, so
+// that changes made by other letstry processes at the same time are not lost.
+// The config file is only replaced if update succeeds.
+func UpdateConfig(update func(*Config) error) error {
 	store := storage.GetStorage()
 
-	file, err := store.OpenFile("config.json")
-	if err != nil {
-		return fmt.Errorf("failed to open config file: %v", err)
-	}
+	return store.Update("config.json", nil, func(data []byte) ([]byte, error) {
+		var cfg *Config
+		if data == nil {
+			// The config file does not exist yet.
+			cfg = getDefaultConfig()
+		} else {
+			cfg = &Config{}
+			if err := json.Unmarshal(data, cfg); err != nil {
+				return nil, fmt.Errorf("failed to decode config file: %v", err)
+			}
+		}
 
-	defer file.Close()
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "    ")
-	if err := encoder.Encode(cfg); err != nil {
-		return fmt.Errorf("failed to encode config file: %v", err)
-	}
+		cfg.path = store.GetAbsolutePath("config.json")
+
+		if err := update(cfg); err != nil {
+			return nil, err
+		}
+
+		data, err := json.MarshalIndent(cfg, "", "    ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode config file: %v", err)
+		}
 
-	return nil
+		return data, nil
+	})
 }
 
 func getDefaultConfig() *Config {
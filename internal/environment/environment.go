@@ -18,8 +18,20 @@ var (
 	ErrEnvironmentNotFound = errors.New("environment not found")
 )
 
+const (
+	// Set in the environment of the editor launched for a session to the ID
+	// of the session.
+	SessionIDVariable = "LT_SESSION_ID"
+	// Set in the environment of the editor launched for a session to the
+	// location of the session.
+	SessionDirVariable = "LT_SESSION_DIR"
+)
+
 type Environment struct {
 	DebuggerAttached bool
+	// The ID of the session letstry is being run from, if it was started
+	// from within a session's editor.
+	SessionID string
 }
 
 func EnvironmentFromContext(ctx context.Context) (Environment, error) {
@@ -33,5 +45,6 @@ func EnvironmentFromContext(ctx context.Context) (Environment, error) {
 func ContextWithEnvironment(ctx context.Context) context.Context {
 	return context.WithValue(ctx, environmentKey, Environment{
 		DebuggerAttached: os.Getenv("DEBUGGER_ATTACHED") == "true",
+		SessionID:        os.Getenv(SessionIDVariable),
 	})
 }
@@ -68,8 +68,9 @@ func New(cfg *LoggerConfig) (*logger, error) {
 	var internalLogger *log.Logger
 
 	if cfg == nil || cfg.LogMode == LogModeConsole {
-		// Write output to the console only.
-		internalLogger = log.New(os.Stdout, "letstry: ", log.LstdFlags)
+		// Write output to the console only. Logs are written to stderr so
+		// that stdout only contains the data produced by commands.
+		internalLogger = log.New(os.Stderr, "letstry: ", log.LstdFlags)
 	} else {
 		switch cfg.LogMode {
 		case LogModeFile:
@@ -77,7 +78,7 @@ func New(cfg *LoggerConfig) (*logger, error) {
 			internalLogger = log.New(file, fmt.Sprintf("%s%s", logPrefix, cfg.Prefix), log.LstdFlags)
 		case LogModeBoth:
 			file, err = storageManager.OpenFile("ltlog.log")
-			internalLogger = log.New(io.MultiWriter(file, os.Stdout), fmt.Sprintf("%s%s", logPrefix, cfg.Prefix), log.LstdFlags)
+			internalLogger = log.New(io.MultiWriter(file, os.Stderr), fmt.Sprintf("%s%s", logPrefix, cfg.Prefix), log.LstdFlags)
 		case LogModeNone:
 			internalLogger = log.New(io.Discard, "", log.LstdFlags)
 		}
@@ -0,0 +1,471 @@
+package manager
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/letstrygo/letstry/internal/logging"
+)
+
+var (
+	ErrArchiveOptionsNotSupported = errors.New("archive options can only be used with archive sources")
+	ErrUnsupportedArchive         = errors.New("unsupported archive format")
+	ErrUnsafeArchiveEntry         = errors.New("archive entry is outside of the destination")
+)
+
+// archiveExtensions are the file extensions recognised as archives.
+var archiveExtensions = []string{".tar.gz", ".tgz", ".zip"}
+
+// ArchiveOptions control how an archive is extracted.
+type ArchiveOptions struct {
+	// The number of leading directories removed from the path of each entry,
+	// such as the single top-level directory of a release tarball. Entries
+	// within fewer directories are not extracted.
+	StripComponents int `json:"strip_components,omitempty"`
+}
+
+// IsZero returns true if none of the options have been set.
+func (o ArchiveOptions) IsZero() bool {
+	return o == ArchiveOptions{}
+}
+
+// looksLikeArchive returns true if value is a local archive file, or an
+// http(s) URL to an archive. No network access is performed.
+func looksLikeArchive(value string) bool {
+	if isArchiveURL(value) {
+		return true
+	}
+
+	if !hasArchiveExtension(value) {
+		return false
+	}
+
+	stat, err := os.Stat(value)
+	return err == nil && stat.Mode().IsRegular()
+}
+
+func isArchiveURL(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	if !strings.EqualFold(u.Scheme, "http") && !strings.EqualFold(u.Scheme, "https") {
+		return false
+	}
+
+	return hasArchiveExtension(u.Path)
+}
+
+func hasArchiveExtension(value string) bool {
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(strings.ToLower(value), ext) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// trimArchiveExtension returns the name of the archive without its
+// extension.
+func trimArchiveExtension(name string) string {
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(strings.ToLower(name), ext) {
+			return name[:len(name)-len(ext)]
+		}
+	}
+
+	return name
+}
+
+// extractArchiveSource extracts the archive at source, which may be a local
+// path or an http(s) URL, into dest.
+func extractArchiveSource(ctx context.Context, source string, dest string, opts ArchiveOptions) error {
+	if !isArchiveURL(source) {
+		return extractArchive(source, dest, opts)
+	}
+
+	logger, err := logging.LoggerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp("", "letstry-archive")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	logger.Printf("downloading archive %s\n", source)
+	err = downloadFile(ctx, source, file)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %v", source, err)
+	}
+
+	return extractArchive(file.Name(), dest, opts)
+}
+
+func downloadFile(ctx context.Context, source string, out io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", res.Status)
+	}
+
+	_, err = io.Copy(out, res.Body)
+	return err
+}
+
+// extractArchive extracts the archive file at name into dest. The format of
+// the archive is detected from its contents rather than its name.
+func extractArchive(name string, dest string, opts ArchiveOptions) error {
+	err := os.MkdirAll(dest, 0755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	header := make([]byte, 4)
+	n, err := io.ReadFull(file, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: %s", ErrUnsupportedArchive, name)
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	switch {
+	case bytes.HasPrefix(header[:n], []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(bufio.NewReader(file))
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+
+		return extractTar(tar.NewReader(gz), dest, opts)
+	case bytes.HasPrefix(header[:n], []byte("PK\x03\x04")):
+		stat, err := file.Stat()
+		if err != nil {
+			return err
+		}
+
+		zr, err := zip.NewReader(file, stat.Size())
+		if err != nil {
+			return err
+		}
+
+		return extractZip(zr, dest, opts)
+	}
+
+	return fmt.Errorf("%w: %s", ErrUnsupportedArchive, name)
+}
+
+func extractTar(tr *tar.Reader, dest string, opts ArchiveOptions) error {
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, ok, err := archiveEntryPath(dest, header.Name, opts)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		mode := os.FileMode(header.Mode).Perm()
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0755)
+		case tar.TypeReg:
+			err = writeArchiveFile(target, tr, mode)
+		case tar.TypeSymlink:
+			err = writeArchiveSymlink(dest, target, header.Linkname)
+		case tar.TypeLink:
+			var linked string
+			linked, ok, err = archiveEntryPath(dest, header.Linkname, opts)
+			if err == nil && ok {
+				err = writeArchiveLink(linked, target)
+			}
+		default:
+			// Devices, fifos and other special files are not extracted.
+		}
+		if err != nil {
+			return fmt.Errorf("failed to extract %s: %w", header.Name, err)
+		}
+	}
+}
+
+func extractZip(zr *zip.Reader, dest string, opts ArchiveOptions) error {
+	for _, entry := range zr.File {
+		target, ok, err := archiveEntryPath(dest, entry.Name, opts)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		err = extractZipEntry(entry, dest, target)
+		if err != nil {
+			return fmt.Errorf("failed to extract %s: %w", entry.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func extractZipEntry(entry *zip.File, dest string, target string) error {
+	mode := entry.Mode()
+	if mode.IsDir() {
+		return os.MkdirAll(target, 0755)
+	}
+
+	contents, err := entry.Open()
+	if err != nil {
+		return err
+	}
+	defer contents.Close()
+
+	if mode&os.ModeSymlink != 0 {
+		link, err := io.ReadAll(contents)
+		if err != nil {
+			return err
+		}
+
+		return writeArchiveSymlink(dest, target, string(link))
+	}
+
+	return writeArchiveFile(target, contents, mode.Perm())
+}
+
+// archiveEntryPath returns the path within dest that the archive entry is
+// extracted to, after stripping leading directories. Entries that are
+// removed entirely by stripping are reported as not ok. Entries that would be
+// written outside of dest are rejected.
+func archiveEntryPath(dest string, name string, opts ArchiveOptions) (string, bool, error) {
+	name = strings.ReplaceAll(name, "\\", "/")
+
+	if path.IsAbs(name) || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return "", false, fmt.Errorf("%w: %s", ErrUnsafeArchiveEntry, name)
+	}
+
+	segments := []string{}
+	for _, segment := range strings.Split(path.Clean(name), "/") {
+		if segment == ".." {
+			return "", false, fmt.Errorf("%w: %s", ErrUnsafeArchiveEntry, name)
+		}
+
+		if segment != "" && segment != "." {
+			segments = append(segments, segment)
+		}
+	}
+
+	if len(segments) <= opts.StripComponents {
+		return "", false, nil
+	}
+
+	target := filepath.Join(dest, filepath.Join(segments[opts.StripComponents:]...))
+	if !isWithinDirectory(dest, target) {
+		return "", false, fmt.Errorf("%w: %s", ErrUnsafeArchiveEntry, name)
+	}
+
+	// Symbolic links extracted by earlier entries are never followed, as
+	// they could lead outside of dest.
+	if err := checkArchiveParents(dest, target); err != nil {
+		return "", false, fmt.Errorf("%w: %s", err, name)
+	}
+
+	return target, true, nil
+}
+
+// checkArchiveParents returns an error if any of the directories between
+// dest and target is a symbolic link.
+func checkArchiveParents(dest string, target string) error {
+	rel, err := filepath.Rel(dest, filepath.Dir(target))
+	if err != nil || rel == "." {
+		return err
+	}
+
+	current := dest
+	for _, segment := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, segment)
+
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			// The remaining directories are created by the extraction.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			return ErrUnsafeArchiveEntry
+		}
+	}
+
+	return nil
+}
+
+// removeArchiveSymlink removes target if it is a symbolic link, so that it is
+// replaced rather than followed.
+func removeArchiveSymlink(target string) error {
+	info, err := os.Lstat(target)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return nil
+	}
+
+	return os.Remove(target)
+}
+
+func isWithinDirectory(dir string, target string) bool {
+	rel, err := filepath.Rel(dir, target)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+func writeArchiveFile(target string, contents io.Reader, mode os.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(target), 0755)
+	if err != nil {
+		return err
+	}
+
+	err = removeArchiveSymlink(target)
+	if err != nil {
+		return err
+	}
+
+	// Files are always writable by their owner, so that the extracted
+	// workspace can be modified and removed.
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode|0600)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, contents)
+	return err
+}
+
+// writeArchiveSymlink creates a symbolic link at target, rejecting links that
+// point outside of dest, or that pass through another symbolic link.
+func writeArchiveSymlink(dest string, target string, link string) error {
+	err := checkArchiveSymlink(dest, target, link)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(target), 0755)
+	if err != nil {
+		return err
+	}
+
+	err = removeArchiveSymlink(target)
+	if err != nil {
+		return err
+	}
+
+	return os.Symlink(link, target)
+}
+
+// checkArchiveSymlink follows the path of the link one segment at a time,
+// rejecting it if it leaves dest at any point or passes through an existing
+// symbolic link. Checking the link as text alone is not enough, as "a/.."
+// leaves dest when a is a link to ".".
+func checkArchiveSymlink(dest string, target string, link string) error {
+	current := filepath.Dir(target)
+	rest := filepath.ToSlash(link)
+
+	if filepath.IsAbs(link) {
+		rel, err := filepath.Rel(dest, link)
+		if err != nil || !isWithinDirectory(dest, link) {
+			return fmt.Errorf("%w: %s -> %s", ErrUnsafeArchiveEntry, target, link)
+		}
+
+		current, rest = dest, filepath.ToSlash(rel)
+	}
+
+	for _, segment := range strings.Split(rest, "/") {
+		switch segment {
+		case "", ".":
+			continue
+		case "..":
+			current = filepath.Dir(current)
+		default:
+			current = filepath.Join(current, segment)
+		}
+
+		if !isWithinDirectory(dest, current) {
+			return fmt.Errorf("%w: %s -> %s", ErrUnsafeArchiveEntry, target, link)
+		}
+
+		info, err := os.Lstat(current)
+		if err == nil && info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%w: %s -> %s", ErrUnsafeArchiveEntry, target, link)
+		}
+	}
+
+	return nil
+}
+
+// writeArchiveLink creates a hard link at target to the previously extracted
+// file at linked. Links to anything other than regular files are rejected,
+// as a hard link to a relative symbolic link would change where it points.
+func writeArchiveLink(linked string, target string) error {
+	info, err := os.Lstat(linked)
+	if err != nil {
+		return err
+	}
+
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%w: %s -> %s", ErrUnsafeArchiveEntry, target, linked)
+	}
+
+	err = os.MkdirAll(filepath.Dir(target), 0755)
+	if err != nil {
+		return err
+	}
+
+	err = removeArchiveSymlink(target)
+	if err != nil {
+		return err
+	}
+
+	return os.Link(linked, target)
+}
@@ -0,0 +1,219 @@
+package manager
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testArchiveEntry struct {
+	name     string
+	typeflag byte
+	body     string
+	link     string
+}
+
+func writeTestTarGz(t *testing.T, entries []testArchiveEntry) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:     entry.name,
+			Typeflag: entry.typeflag,
+			Linkname: entry.link,
+			Mode:     0644,
+			Size:     int64(len(entry.body)),
+		}
+		if entry.typeflag == tar.TypeDir {
+			header.Mode = 0755
+		}
+		if entry.typeflag != tar.TypeReg {
+			header.Size = 0
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+
+		if entry.typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(entry.body)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	name := filepath.Join(t.TempDir(), "archive.tar.gz")
+	if err := os.WriteFile(name, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return name
+}
+
+func readTestFile(t *testing.T, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestExtractArchive(t *testing.T) {
+	archive := writeTestTarGz(t, []testArchiveEntry{
+		{name: "project/", typeflag: tar.TypeDir},
+		{name: "project/a.txt", typeflag: tar.TypeReg, body: "a"},
+		{name: "project/sub/link", typeflag: tar.TypeSymlink, link: "../a.txt"},
+		{name: "project/hard", typeflag: tar.TypeLink, link: "project/a.txt"},
+	})
+
+	dest := filepath.Join(t.TempDir(), "dest")
+	err := extractArchive(archive, dest, ArchiveOptions{StripComponents: 1})
+	if err != nil {
+		t.Fatalf("extractArchive() error = %v", err)
+	}
+
+	for _, name := range []string{"a.txt", "sub/link", "hard"} {
+		if got := readTestFile(t, filepath.Join(dest, name)); got != "a" {
+			t.Errorf("%s = %q, want %q", name, got, "a")
+		}
+	}
+}
+
+func TestExtractZipArchive(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	w, err := zw.Create("project/b.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "archive.zip")
+	if err := os.WriteFile(archive, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "dest")
+	err = extractArchive(archive, dest, ArchiveOptions{StripComponents: 1})
+	if err != nil {
+		t.Fatalf("extractArchive() error = %v", err)
+	}
+
+	if got := readTestFile(t, filepath.Join(dest, "b.txt")); got != "b" {
+		t.Errorf("b.txt = %q, want %q", got, "b")
+	}
+}
+
+func TestExtractArchiveRejectsUnsafeEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []testArchiveEntry
+	}{
+		{
+			name: "parent directory",
+			entries: []testArchiveEntry{
+				{name: "../pwned", typeflag: tar.TypeReg, body: "x"},
+			},
+		},
+		{
+			name: "absolute path",
+			entries: []testArchiveEntry{
+				{name: "/pwned", typeflag: tar.TypeReg, body: "x"},
+			},
+		},
+		{
+			name: "symlink outside",
+			entries: []testArchiveEntry{
+				{name: "link", typeflag: tar.TypeSymlink, link: "../.."},
+			},
+		},
+		{
+			name: "chained symlinks",
+			entries: []testArchiveEntry{
+				{name: "x", typeflag: tar.TypeSymlink, link: "."},
+				{name: "x/y", typeflag: tar.TypeSymlink, link: ".."},
+				{name: "y/pwned", typeflag: tar.TypeReg, body: "x"},
+			},
+		},
+		{
+			name: "symlink through symlink",
+			entries: []testArchiveEntry{
+				{name: "a", typeflag: tar.TypeSymlink, link: "."},
+				{name: "b", typeflag: tar.TypeSymlink, link: "a/.."},
+				{name: "b/pwned", typeflag: tar.TypeReg, body: "x"},
+			},
+		},
+		{
+			name: "file through symlink",
+			entries: []testArchiveEntry{
+				{name: "d", typeflag: tar.TypeSymlink, link: "."},
+				{name: "d/pwned", typeflag: tar.TypeReg, body: "x"},
+			},
+		},
+		{
+			name: "hard link through symlink",
+			entries: []testArchiveEntry{
+				{name: "f", typeflag: tar.TypeReg, body: "x"},
+				{name: "d", typeflag: tar.TypeSymlink, link: "."},
+				{name: "d/pwned", typeflag: tar.TypeLink, link: "f"},
+			},
+		},
+		{
+			name: "hard link to symlink",
+			entries: []testArchiveEntry{
+				{name: "sub/s", typeflag: tar.TypeSymlink, link: ".."},
+				{name: "t", typeflag: tar.TypeLink, link: "sub/s"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := writeTestTarGz(t, tt.entries)
+
+			root := t.TempDir()
+			dest := filepath.Join(root, "dest")
+
+			err := extractArchive(archive, dest, ArchiveOptions{})
+			if !errors.Is(err, ErrUnsafeArchiveEntry) {
+				t.Fatalf("extractArchive() error = %v, want %v", err, ErrUnsafeArchiveEntry)
+			}
+
+			entries, err := os.ReadDir(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, entry := range entries {
+				if entry.Name() != "dest" {
+					t.Errorf("extracted %s outside of the destination", entry.Name())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package manager
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/go-git/go-git/v5"
+
+	"github.com/letstrygo/letstry/internal/config"
+	"github.com/letstrygo/letstry/internal/logging"
+)
+
+const (
+	mirrorMetadataFileName = "letstry-mirror.json"
+)
+
+var (
+	gitCacheDirectory = filepath.Join("cache", "git")
+
+	ErrMirrorNotFound = errors.New("repository is not cached")
+)
+
+// RepositoryMirror is a bare mirror of a git repository kept in the cache.
+type RepositoryMirror struct {
+	URL  string `json:"url"`
+	Path string `json:"path"`
+	// The size of the mirror in bytes, recorded when it was last fetched.
+	Size        int64     `json:"size"`
+	LastUsed    time.Time `json:"last_used"`
+	LastFetched time.Time `json:"last_fetched"`
+}
+
+func (m RepositoryMirror) String() string {
+	used := color.BlueString("(%s)", m.LastUsed.Format("2006-01-02 15:04:05"))
+	return fmt.Sprintf("url=%s, size=%d, last_used=%s", color.YellowString(m.URL), m.Size, used)
+}
+
+func normalizeMirrorURL(url string) string {
+	return strings.TrimSuffix(strings.TrimSpace(url), "/")
+}
+
+// mirrorStoragePath returns the location of the mirror for url, relative to
+// the storage directory.
+func mirrorStoragePath(url string) string {
+	sum := sha256.Sum256([]byte(normalizeMirrorURL(url)))
+	return filepath.Join(gitCacheDirectory, hex.EncodeToString(sum[:])[:16])
+}
+
+func (s *manager) readMirror(storagePath string) (RepositoryMirror, error) {
+	var mirror RepositoryMirror
+
+	path := s.storage.GetAbsolutePath(storagePath)
+
+	data, err := os.ReadFile(filepath.Join(path, mirrorMetadataFileName))
+	if err != nil {
+		return mirror, err
+	}
+
+	if err := json.Unmarshal(data, &mirror); err != nil {
+		return mirror, fmt.Errorf("failed to decode mirror metadata: %v", err)
+	}
+
+	mirror.Path = path
+	return mirror, nil
+}
+
+func writeMirror(mirror RepositoryMirror) error {
+	data, err := json.MarshalIndent(mirror, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(mirror.Path, mirrorMetadataFileName), data, 0644)
+}
+
+// cachedMirror returns the path to an up to date mirror of the repository,
+// creating the mirror if required. The mirror is not used when the cache is
+// disabled, or when a shallow clone has been requested as shallow clones
+// can not be made from a local mirror.
+func (s *manager) cachedMirror(ctx context.Context, url string, opts RepositoryOptions) (string, bool) {
+	cfg, err := config.GetConfig()
+	if err != nil || cfg.Cache.Disabled || opts.Depth > 0 {
+		return "", false
+	}
+
+	logger, err := logging.LoggerFromContext(ctx)
+	if err != nil {
+		return "", false
+	}
+
+	mirror, err := s.updateMirror(ctx, url, false)
+	if err != nil {
+		logger.Printf("failed to update repository cache: %v\n", err)
+		return "", false
+	}
+
+	err = s.enforceCacheSize(ctx, cfg.Cache.GetMaxSize(), mirror.Path)
+	if err != nil {
+		logger.Printf("failed to prune repository cache: %v\n", err)
+	}
+
+	return mirror.Path, true
+}
+
+// updateMirror creates the mirror for url, or fetches updates for an existing
+// mirror. When the mirror exists and fetching fails, for example because the
+// network is not available, the existing mirror is used unless required is
+// set.
+func (s *manager) updateMirror(ctx context.Context, url string, required bool) (RepositoryMirror, error) {
+	storagePath := mirrorStoragePath(url)
+
+	err := s.storage.CreateDirectory(gitCacheDirectory)
+	if err != nil {
+		return RepositoryMirror{}, err
+	}
+
+	lock, err := s.storage.Lock(storagePath)
+	if err != nil {
+		return RepositoryMirror{}, err
+	}
+	defer lock.Unlock()
+
+	logger, err := logging.LoggerFromContext(ctx)
+	if err != nil {
+		return RepositoryMirror{}, err
+	}
+
+	now := time.Now()
+	path := s.storage.GetAbsolutePath(storagePath)
+	fetched := false
+
+	mirror, err := s.readMirror(storagePath)
+	if err != nil {
+		// The mirror does not exist, or was not created successfully.
+		_ = os.RemoveAll(path)
+
+		logger.Printf("caching repository %s\n", url)
+		_, err = git.PlainCloneContext(ctx, path, true, &git.CloneOptions{
+			URL:    url,
+			Mirror: true,
+		})
+		if err != nil {
+			_ = os.RemoveAll(path)
+			return RepositoryMirror{}, err
+		}
+
+		mirror = RepositoryMirror{
+			URL:         normalizeMirrorURL(url),
+			Path:        path,
+			LastFetched: now,
+		}
+		fetched = true
+	} else {
+		repo, err := git.PlainOpen(path)
+		if err != nil {
+			return RepositoryMirror{}, err
+		}
+
+		err = repo.FetchContext(ctx, &git.FetchOptions{
+			Force: true,
+			Prune: true,
+		})
+		switch {
+		case err == nil:
+			mirror.LastFetched = now
+			fetched = true
+		case errors.Is(err, git.NoErrAlreadyUpToDate):
+			mirror.LastFetched = now
+		case required:
+			return RepositoryMirror{}, fmt.Errorf("failed to fetch %s: %v", url, err)
+		default:
+			logger.Printf("failed to fetch %s, using cached copy: %v\n", url, err)
+		}
+	}
+
+	// The size is recorded in the metadata so that listing the cache does
+	// not walk every mirror. It only changes when something was fetched.
+	if fetched || mirror.Size == 0 {
+		mirror.Size, err = directorySize(path)
+		if err != nil {
+			return RepositoryMirror{}, err
+		}
+	}
+
+	mirror.LastUsed = now
+	err = writeMirror(mirror)
+	if err != nil {
+		return RepositoryMirror{}, err
+	}
+
+	return mirror, nil
+}
+
+// ListRepositoryCache returns the cached repository mirrors, most recently
+// used first.
+func (s *manager) ListRepositoryCache(ctx context.Context) ([]RepositoryMirror, error) {
+	if !s.storage.DirectoryExists(gitCacheDirectory) {
+		return []RepositoryMirror{}, nil
+	}
+
+	dirs, err := s.storage.ListDirectories(gitCacheDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	mirrors := []RepositoryMirror{}
+	for _, dir := range dirs {
+		mirror, err := s.readMirror(filepath.Join(gitCacheDirectory, dir))
+		if err != nil {
+			// Skip mirrors that are still being created.
+			continue
+		}
+
+		mirrors = append(mirrors, mirror)
+	}
+
+	sort.Slice(mirrors, func(i, j int) bool {
+		return mirrors[i].LastUsed.After(mirrors[j].LastUsed)
+	})
+
+	return mirrors, nil
+}
+
+// selectMirrors returns every cached mirror, or only the mirror for url if
+// it is not empty.
+func (s *manager) selectMirrors(ctx context.Context, url string) ([]RepositoryMirror, error) {
+	mirrors, err := s.ListRepositoryCache(ctx)
+	if err != nil || url == "" {
+		return mirrors, err
+	}
+
+	for _, mirror := range mirrors {
+		if mirror.URL == normalizeMirrorURL(url) {
+			return []RepositoryMirror{mirror}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrMirrorNotFound, url)
+}
+
+// PruneRepositoryCache removes the mirror for url, or every mirror if url is
+// empty.
+func (s *manager) PruneRepositoryCache(ctx context.Context, url string) ([]RepositoryMirror, error) {
+	mirrors, err := s.selectMirrors(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, mirror := range mirrors {
+		err := s.removeMirror(mirror)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return mirrors, nil
+}
+
+// RefreshRepositoryCache fetches updates for the mirror of url, or for every
+// mirror if url is empty.
+func (s *manager) RefreshRepositoryCache(ctx context.Context, url string) ([]RepositoryMirror, error) {
+	mirrors, err := s.selectMirrors(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshed := []RepositoryMirror{}
+	for _, mirror := range mirrors {
+		mirror, err := s.updateMirror(ctx, mirror.URL, true)
+		if err != nil {
+			return refreshed, err
+		}
+
+		refreshed = append(refreshed, mirror)
+	}
+
+	return refreshed, nil
+}
+
+func (s *manager) removeMirror(mirror RepositoryMirror) error {
+	storagePath := mirrorStoragePath(mirror.URL)
+
+	lock, err := s.storage.Lock(storagePath)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	return s.storage.DeleteDirectory(storagePath)
+}
+
+// enforceCacheSize removes the least recently used mirrors, other than the
+// mirror at keep, until the cache is within maxSize.
+func (s *manager) enforceCacheSize(ctx context.Context, maxSize int64, keep string) error {
+	mirrors, err := s.ListRepositoryCache(ctx)
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, mirror := range mirrors {
+		total += mirror.Size
+	}
+
+	// Mirrors are ordered most recently used first.
+	for i := len(mirrors) - 1; i >= 0 && total > maxSize; i-- {
+		if mirrors[i].Path == keep {
+			continue
+		}
+
+		err := s.removeMirror(mirrors[i])
+		if err != nil {
+			return err
+		}
+
+		total -= mirrors[i].Size
+	}
+
+	return nil
+}
@@ -1,30 +1,34 @@
 package manager
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
-	"runtime"
-	"strconv"
-	"strings"
 	"time"
 
-	"github.com/go-git/go-git/v5"
 	"github.com/letstrygo/letstry/internal/config"
 	"github.com/letstrygo/letstry/internal/config/editors"
 	"github.com/letstrygo/letstry/internal/environment"
+	"github.com/letstrygo/letstry/internal/logging"
 	"github.com/letstrygo/letstry/internal/util/identifier"
 	"github.com/otiai10/copy"
-	"github.com/samber/lo"
 )
 
 type CreateSessionArguments struct {
 	Source             string `json:"source"`
 	ForceRequireExport bool   `json:"force_require_export"`
+	// Values for the variables declared in the templates manifest. These
+	// take precedence over values loaded from ValuesFile.
+	Values map[string]string `json:"values"`
+	// Path to a JSON file containing values for the templates variables.
+	ValuesFile string `json:"values_file"`
+	// Options used when the source is a git repository.
+	Repository RepositoryOptions `json:"repository"`
+	// Options used when the source is an archive.
+	Archive ArchiveOptions `json:"archive"`
 }
 
 func (s *manager) CreateSession(ctx context.Context, args CreateSessionArguments) (*Session, error) {
@@ -42,11 +46,34 @@ func (s *manager) CreateSession(ctx context.Context, args CreateSessionArguments
 		return nil, err
 	}
 
-	src, err := s.parseSessionSource(ctx, args.Source)
+	err = editor.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid editor %s: %v", editor.Name, err)
+	}
+
+	src, opts, err := s.parseSessionSource(ctx, args.Source)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse session source: %v", err)
 	}
 
+	// Options provided on the command line take precedence over those of
+	// the source alias.
+	args.Repository = opts.Merge(args.Repository)
+
+	if src.SourceType != SessionSourceTypeRepository && !args.Repository.IsZero() {
+		return nil, ErrRepositoryOptionsNotSupported
+	}
+
+	if src.SourceType != SessionSourceTypeArchive && !args.Archive.IsZero() {
+		return nil, ErrArchiveOptionsNotSupported
+	}
+
+	// Resolve template variables before anything is written to disk.
+	variables, err := s.resolveTemplateVariables(ctx, src, args)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create temporary directory
 	projectName := fmt.Sprintf("%v-lt%d", src.ShortValue(), time.Now().Unix())
 	storageDir := filepath.Join(cfg.LTPath, projectName)
@@ -75,13 +102,22 @@ func (s *manager) CreateSession(ctx context.Context, args CreateSessionArguments
 	id := identifier.NewID()
 
 	// Fill workspace based on source type.
-	err = s.fillWorkspace(ctx, src, storageDir)
+	err = s.fillWorkspace(ctx, src, storageDir, variables, args.Repository, args.Archive)
+	if err != nil {
+		_ = os.RemoveAll(storageDir)
+		return nil, err
+	}
+
+	// Run the templates post-create hooks, removing the partially created
+	// workspace if any of them fail.
+	err = s.runPostCreateHooks(ctx, src, storageDir, variables)
 	if err != nil {
+		_ = os.RemoveAll(storageDir)
 		return nil, err
 	}
 
 	// Launch the editor
-	cmd, err := s.launchEditor(ctx, editor, storageDir)
+	cmd, err := s.launchEditor(ctx, editor, id, src, storageDir)
 	if err != nil {
 		return nil, err
 	}
@@ -89,17 +125,63 @@ func (s *manager) CreateSession(ctx context.Context, args CreateSessionArguments
 	// Monitor session, automatically purging it from the cache once closed.
 	if requireExport {
 		// Cache the session in the file system.
-		session, err := s.prepareMonitor(ctx, id, cmd, editor, src, storageDir)
+		session, err := s.prepareMonitor(ctx, id, cmd, editor, src, storageDir, variables)
 		if err != nil {
 			return nil, err
 		}
 
-		return session, s.monitor(ctx, session)
+		return session, s.superviseSession(ctx, cmd, session)
+	}
+
+	// Projects are kept, but editors that are waited on still block until
+	// they exit.
+	if editor.TrackingTypeOrDefault() == editors.TrackingTypeWait {
+		return nil, waitForEditor(cmd)
 	}
 
 	return nil, nil
 }
 
+// superviseSession arranges for the session to be removed once its editor
+// has been closed. Sessions using the wait tracking type are removed by this
+// process as soon as the editor exits, or by the daemon if this process exits
+// first, all other sessions are monitored.
+func (s *manager) superviseSession(ctx context.Context, cmd *exec.Cmd, session *Session) error {
+	if session.Editor.TrackingTypeOrDefault() != editors.TrackingTypeWait {
+		return s.monitor(ctx, session)
+	}
+
+	logger, err := logging.LoggerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The daemon cleans up the session should this process be killed
+	// before the editor exits.
+	if err := s.ensureDaemon(ctx); err != nil {
+		logger.Printf("failed to start daemon: %v\n", err)
+	}
+
+	err = waitForEditor(cmd)
+	if err != nil {
+		logger.Printf("editor exited: %v\n", err)
+	}
+
+	logger.Printf("cleaning up session: %s (editor exited)\n", session.ID)
+	return s.removeSession(ctx, session.ID)
+}
+
+// waitForEditor waits for the editor process to exit. Interrupts are left
+// for the editor to handle rather than ending letstry while the editor is
+// still running, as the session would not be cleaned up.
+func waitForEditor(cmd *exec.Cmd) error {
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	return cmd.Wait()
+}
+
 func (s *manager) monitor(ctx context.Context, session *Session) error {
 	appEnvironment, err := environment.EnvironmentFromContext(ctx)
 	if err != nil {
@@ -117,135 +199,96 @@ func (s *manager) monitor(ctx context.Context, session *Session) error {
 			return err
 		}
 	} else {
-		// Call this application again, but start it in the background as it's own process.
-		// This will allow the user to continue using the current terminal session.
-		cmd := exec.Command(os.Args[0], "monitor", fmt.Sprintf("%v", session.Editor.ProcessCaptureDelay), session.Location, fmt.Sprintf("%v", session.PID), session.Editor.TrackingType.String())
-		err = cmd.Start()
+		// Hand the session to the background daemon, which supervises every
+		// session and removes them once their editor has been closed.
+		err = s.RegisterSession(ctx, session.ID)
 		if err != nil {
-			return fmt.Errorf("failed to start monitor process: %v", err)
+			return fmt.Errorf("failed to register session with daemon: %v", err)
 		}
 	}
 
 	return nil
 }
 
-func (s *manager) parseSessionSource(ctx context.Context, source string) (Source, error) {
-	var zeroValue Source
-
-	sourceType, err := s.GetSessionSourceType(ctx, source)
-	if err != nil {
-		return zeroValue, err
+func (s *manager) resolveTemplateVariables(ctx context.Context, source Source, args CreateSessionArguments) (map[string]string, error) {
+	if source.SourceType != SessionSourceTypeTemplate {
+		return nil, nil
 	}
 
-	return Source{sourceType, source}, nil
-}
+	name, rev := ParseTemplateReference(source.Value)
 
-func (s *manager) prepareMonitor(ctx context.Context, id identifier.ID, cmd *exec.Cmd, editor editors.Editor, source Source, storageDir string) (*Session, error) {
-	pid, err := s.locatePid(cmd.Process.Pid)
+	template, err := s.GetTemplate(ctx, name.String())
 	if err != nil {
 		return nil, err
 	}
 
-	session := Session{
-		ID:       id,
-		Location: storageDir,
-		PID:      pid,
-		Source:   source,
-		Editor:   editor,
-	}
-
-	// Save the session
-	err = s.addSession(ctx, session)
+	manifest, err := s.templateManifestAt(ctx, template, rev)
 	if err != nil {
 		return nil, err
 	}
 
-	return &session, nil
-}
-
-// locatePid will attempt to locate the PID of the most recent vscode process
-// by assuming that vscode processes will have the ps cmd of `/usr/share/code/code`
-func (s *manager) locatePid(pid int) (int, error) {
-	// This functionality is only supported on linux.
-	if runtime.GOOS != "linux" {
-		return pid, nil
+	values := map[string]string{}
+	if args.ValuesFile != "" {
+		values, err = LoadTemplateValuesFile(args.ValuesFile)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	time.Sleep(2 * time.Second)
-
-	psCmd := exec.Command(
-		"ps",
-		"-eo", "pid,etimes,cmd",
-		"--sort=etimes",
-	)
-
-	grepCmd := exec.Command(
-		"grep",
-		"/usr/share/code/code",
-	)
-
-	// Pipe psCmd's stdout to grepCmd's stdin
-	psOut, err := psCmd.StdoutPipe()
-	if err != nil {
-		panic(err)
+	for key, value := range args.Values {
+		values[key] = value
 	}
-	grepCmd.Stdin = psOut
 
-	// Capture grepCmd's output
-	var out bytes.Buffer
-	grepCmd.Stdout = &out
+	return manifest.ResolveVariables(values)
+}
 
-	// Start psCmd
-	if err := psCmd.Start(); err != nil {
-		return pid, err
+func (s *manager) prepareMonitor(ctx context.Context, id identifier.ID, cmd *exec.Cmd, editor editors.Editor, source Source, storageDir string, variables map[string]string) (*Session, error) {
+	pid, err := s.locatePid(editor, cmd.Process.Pid, storageDir)
+	if err != nil {
+		return nil, err
 	}
 
-	// Start grepCmd
-	if err := grepCmd.Start(); err != nil {
-		return pid, err
+	session := Session{
+		ID:        id,
+		Location:  storageDir,
+		PID:       pid,
+		Source:    source,
+		Editor:    editor,
+		Variables: variables,
 	}
 
-	// Wait for psCmd to finish
-	if err := psCmd.Wait(); err != nil {
-		return pid, err
+	// The daemon removes the session if this process exits without having
+	// removed it.
+	if editor.TrackingTypeOrDefault() == editors.TrackingTypeWait {
+		session.OwnerPID = os.Getpid()
 	}
 
-	// Wait for grepCmd to finish
-	if err := grepCmd.Wait(); err != nil {
-		return pid, err
+	// Save the session
+	err = s.addSession(ctx, session)
+	if err != nil {
+		return nil, err
 	}
 
-	output := out.String()
-
-	// Split output into lines and trim whitespace
-	lines := lo.FilterMap(
-		strings.Split(output, "\n"),
-		func(line string, _ int) (string, bool) {
-			trimmed := strings.TrimSpace(line)
-			return trimmed, trimmed != ""
-		},
-	)
+	return &session, nil
+}
 
-	// Get the second line (index 1), if it exists
-	if len(lines) > 1 {
-		fields := strings.Fields(lines[1])
-		if len(fields) > 0 {
-			_pid, err := strconv.Atoi(fields[0])
-			if err != nil {
-				return pid, err
-			}
-			return _pid, nil
-		}
+func (s *manager) launchEditor(ctx context.Context, editor editors.Editor, id identifier.ID, source Source, tempDir string) (*exec.Cmd, error) {
+	cmd, err := editor.Command(editors.LaunchContext{
+		Path:      tempDir,
+		SessionID: id.String(),
+		Source:    source.Value,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return pid, nil
-}
-
-func (s *manager) launchEditor(ctx context.Context, editor editors.Editor, tempDir string) (*exec.Cmd, error) {
-	cfgArgs := strings.Split(editor.Args, " ")
-	cmdArgs := append(cfgArgs, tempDir)
-	cmd := exec.Command(editor.ExecPath, cmdArgs...)
-	err := cmd.Start()
+	// Let commands run from within the editor identify the session.
+	cmd.Env = append(
+		os.Environ(),
+		fmt.Sprintf("%s=%s", environment.SessionIDVariable, id),
+		fmt.Sprintf("%s=%s", environment.SessionDirVariable, tempDir),
+	)
+	err = cmd.Start()
 	if err != nil {
 		return nil, fmt.Errorf("failed to run editor: %v", err)
 	}
@@ -253,34 +296,69 @@ func (s *manager) launchEditor(ctx context.Context, editor editors.Editor, tempD
 	return cmd, nil
 }
 
-func (s *manager) fillWorkspace(ctx context.Context, source Source, tempDir string) error {
+func (s *manager) fillWorkspace(ctx context.Context, source Source, tempDir string, variables map[string]string, repository RepositoryOptions, archive ArchiveOptions) error {
 	switch source.SourceType {
 	case SessionSourceTypeBlank:
 		return nil
 	case SessionSourceTypeDirectory:
 		return s.fillWorkspaceFromDirectory(ctx, source, tempDir)
 	case SessionSourceTypeRepository:
-		return s.fillWorkspaceFromRepository(ctx, source, tempDir)
+		return s.fillWorkspaceFromRepository(ctx, source, tempDir, repository)
 	case SessionSourceTypeTemplate:
-		return s.fillWorkspaceFromTemplate(ctx, source, tempDir)
+		return s.fillWorkspaceFromTemplate(ctx, source, tempDir, variables)
+	case SessionSourceTypeArchive:
+		return s.fillWorkspaceFromArchive(ctx, source, tempDir, archive)
 	}
 
 	return ErrInvalidSessionSource
 }
 
-func (s *manager) fillWorkspaceFromTemplate(ctx context.Context, source Source, tempDir string) error {
+func (s *manager) fillWorkspaceFromTemplate(ctx context.Context, source Source, tempDir string, variables map[string]string) error {
+	name, rev := ParseTemplateReference(source.Value)
+
 	// Check if the specified template exists.
-	template, err := s.GetTemplate(ctx, source.Value)
+	template, err := s.GetTemplate(ctx, name.String())
 	if err != nil {
 		return err
 	}
 
+	templateDir := template.AbsolutePath(ctx)
+
+	// Historical revisions are exported from the templates repository.
+	if rev != "" {
+		templateDir, err = os.MkdirTemp("", "letstry-template")
+		if err != nil {
+			return fmt.Errorf("failed to load template %s: %s", source, err)
+		}
+		defer os.RemoveAll(templateDir)
+
+		err = s.exportTemplateRevision(ctx, template, rev, templateDir)
+		if err != nil {
+			return fmt.Errorf("failed to load template %s: %s", source, err)
+		}
+	}
+
+	// Templates with a manifest are rendered, otherwise they are copied as is.
+	if _, err := os.Stat(filepath.Join(templateDir, TemplateManifestFileName)); err == nil {
+		manifest, err := LoadTemplateManifest(templateDir)
+		if err != nil {
+			return err
+		}
+
+		err = renderTemplate(manifest, templateDir, tempDir, variables)
+		if err != nil {
+			return fmt.Errorf("failed to load template %s: %s", source, err)
+		}
+
+		return nil
+	}
+
 	// Copy the template to the temporary directory
-	err = copy.Copy(template.AbsolutePath(ctx), tempDir, copy.Options{
+	err = copy.Copy(templateDir, tempDir, copy.Options{
 		Skip: func(srcinfo os.FileInfo, src, dest string) (bool, error) {
 			// Don't include repository information if the source
-			// is a git repository.
-			return srcinfo.IsDir() && srcinfo.Name() == ".git", nil
+			// is a git repository, or the templates history.
+			return srcinfo.IsDir() && isTemplateRepository(srcinfo.Name()), nil
 		},
 	})
 	if err != nil {
@@ -290,10 +368,8 @@ func (s *manager) fillWorkspaceFromTemplate(ctx context.Context, source Source,
 	return nil
 }
 
-func (s *manager) fillWorkspaceFromRepository(ctx context.Context, source Source, tempDir string) error {
-	_, err := git.PlainClone(tempDir, false, &git.CloneOptions{
-		URL: source.Value,
-	})
+func (s *manager) fillWorkspaceFromRepository(ctx context.Context, source Source, tempDir string, opts RepositoryOptions) error {
+	err := s.cloneRepository(ctx, source.Value, tempDir, opts)
 	if err != nil {
 		return fmt.Errorf("failed to clone repository: %v", err)
 	}
@@ -301,6 +377,15 @@ func (s *manager) fillWorkspaceFromRepository(ctx context.Context, source Source
 	return nil
 }
 
+func (s *manager) fillWorkspaceFromArchive(ctx context.Context, source Source, tempDir string, opts ArchiveOptions) error {
+	err := extractArchiveSource(ctx, source.Value, tempDir, opts)
+	if err != nil {
+		return fmt.Errorf("failed to extract archive: %v", err)
+	}
+
+	return nil
+}
+
 func (s *manager) fillWorkspaceFromDirectory(ctx context.Context, source Source, tempDir string) error {
 	absPath, err := filepath.Abs(source.Value)
 	if err != nil {
@@ -311,8 +396,13 @@ func (s *manager) fillWorkspaceFromDirectory(ctx context.Context, source Source,
 		return fmt.Errorf("directory %s does not exist", absPath)
 	}
 
+	skip, err := ignoreRules(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to load ignore rules: %v", err)
+	}
+
 	// Copy the directory to the temporary directory
-	err = copy.Copy(absPath, tempDir)
+	err = copy.Copy(absPath, tempDir, copy.Options{Skip: skip})
 	if err != nil {
 		return fmt.Errorf("failed to copy directory: %v", err)
 	}
@@ -321,47 +411,15 @@ func (s *manager) fillWorkspaceFromDirectory(ctx context.Context, source Source,
 }
 
 func (s *manager) addSession(ctx context.Context, sess Session) error {
-	sessions, err := s.ListSessions(ctx)
-	if err != nil {
-		return err
-	}
-
-	// check if the session already exists by the same name
-	for _, session := range sessions {
-		if session.ID == sess.ID {
-			return fmt.Errorf("session with ID %s already exists", sess.ID)
+	return s.updateSessions(ctx, func(sessions []Session) ([]Session, error) {
+		// check if the session already exists by the same name
+		for _, session := range sessions {
+			if session.ID == sess.ID {
+				return nil, fmt.Errorf("session with ID %s already exists", sess.ID)
+			}
 		}
-	}
-
-	// add the session to the list of sessions
-	sessions = append(sessions, sess)
-
-	// save the sessions
-	file, err := s.storage.OpenFile("sessions.json")
-	if err != nil {
-		return fmt.Errorf("failed to open sessions file: %v", err)
-	}
-	defer file.Close()
-
-	data, err := json.MarshalIndent(sessions, "", "    ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal sessions: %v", err)
-	}
-
-	err = file.Truncate(0)
-	if err != nil {
-		return fmt.Errorf("failed to truncate sessions: %v", err)
-	}
 
-	_, err = file.Write(data)
-	if err != nil {
-		return fmt.Errorf("failed to write sessions: %v", err)
-	}
-
-	err = file.Sync()
-	if err != nil {
-		return fmt.Errorf("failed to sync sessions file: %v", err)
-	}
-
-	return nil
+		// add the session to the list of sessions
+		return append(sessions, sess), nil
+	})
 }
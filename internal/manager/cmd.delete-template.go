@@ -12,7 +12,7 @@ func (s *manager) DeleteTemplate(ctx context.Context, t Template) error {
 		return err
 	}
 
-	err = s.storage.DeleteDirectory(t.StoragePath())
+	err = s.trashTemplate(ctx, t)
 	if err != nil {
 		return err
 	}
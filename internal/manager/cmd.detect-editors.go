@@ -0,0 +1,79 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/letstrygo/letstry/internal/config"
+	"github.com/letstrygo/letstry/internal/config/editors"
+	"github.com/letstrygo/letstry/internal/logging"
+	"github.com/letstrygo/letstry/internal/util/prompt"
+)
+
+// DetectedEditor is an installed editor found using its preset.
+type DetectedEditor struct {
+	editors.Editor
+	// Whether an editor with the same name is already configured.
+	Configured bool `json:"configured"`
+}
+
+type DetectEditorsArguments struct {
+	// Add the detected editors that are not configured without asking for
+	// confirmation.
+	Yes bool
+}
+
+// DetectEditors returns the installed editors that letstry has a preset for.
+// The user is asked whether each editor that is not configured should be
+// added to the configuration.
+func (s *manager) DetectEditors(ctx context.Context, args DetectEditorsArguments) ([]DetectedEditor, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	logger, err := logging.LoggerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	detected := []DetectedEditor{}
+	for _, editor := range editors.DetectEditors() {
+		_, err := cfg.GetEditor(editor.Name.String())
+		detected = append(detected, DetectedEditor{
+			Editor:     editor,
+			Configured: err == nil,
+		})
+	}
+
+	for i, editor := range detected {
+		if editor.Configured {
+			continue
+		}
+
+		if !args.Yes {
+			confirmed, err := prompt.Confirm(fmt.Sprintf("found %s at %s, add it to the configuration?", editor.Name, editor.ExecPath))
+			if errors.Is(err, prompt.ErrNotInteractive) {
+				logger.Printf("found %s at %s, use --yes to add it to the configuration\n", editor.Name, editor.ExecPath)
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if !confirmed {
+				continue
+			}
+		}
+
+		err = s.AddEditor(ctx, editor.Editor)
+		if err != nil {
+			return nil, err
+		}
+
+		detected[i].Configured = true
+	}
+
+	return detected, nil
+}
@@ -0,0 +1,143 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/letstrygo/letstry/internal/config"
+	"github.com/letstrygo/letstry/internal/config/editors"
+	"github.com/letstrygo/letstry/internal/logging"
+)
+
+var (
+	ErrEditorExists       = errors.New("editor already exists")
+	ErrEditorNotInstalled = errors.New("editor executable not found")
+	ErrEditorIsDefault    = errors.New("editor is the default editor")
+)
+
+// AddEditor validates the editor and adds it to the configuration.
+func (s *manager) AddEditor(ctx context.Context, editor editors.Editor) error {
+	logger, err := logging.LoggerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return config.UpdateConfig(func(cfg *config.Config) error {
+		if _, err := cfg.GetEditor(editor.Name.String()); err == nil {
+			return fmt.Errorf("%w: %s", ErrEditorExists, editor.Name)
+		}
+
+		editor, err := checkEditor(editor)
+		if err != nil {
+			return err
+		}
+
+		logger.Printf("adding editor: %s\n", editor.String())
+		cfg.AvailableEditors = append(cfg.AvailableEditors, editor)
+		return nil
+	})
+}
+
+// UpdateEditor replaces the configuration of the named editor with the
+// result of update. The editor can not be renamed.
+func (s *manager) UpdateEditor(ctx context.Context, editorName string, update func(editors.Editor) (editors.Editor, error)) (editors.Editor, error) {
+	logger, err := logging.LoggerFromContext(ctx)
+	if err != nil {
+		return editors.Editor{}, err
+	}
+
+	var updated editors.Editor
+
+	err = config.UpdateConfig(func(cfg *config.Config) error {
+		for i, editor := range cfg.AvailableEditors {
+			if editor.Name.String() != editorName {
+				continue
+			}
+
+			editor, err := update(editor)
+			if err != nil {
+				return err
+			}
+
+			editor.Name = cfg.AvailableEditors[i].Name
+			editor, err = checkEditor(editor)
+			if err != nil {
+				return err
+			}
+
+			logger.Printf("updating editor: %s\n", editor.String())
+			cfg.AvailableEditors[i] = editor
+			updated = editor
+			return nil
+		}
+
+		return fmt.Errorf("editor %s not found", editorName)
+	})
+	if err != nil {
+		return editors.Editor{}, err
+	}
+
+	return updated, nil
+}
+
+// RemoveEditor removes the named editor from the configuration. The default
+// editor can not be removed.
+func (s *manager) RemoveEditor(ctx context.Context, editorName string) error {
+	logger, err := logging.LoggerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return config.UpdateConfig(func(cfg *config.Config) error {
+		editor, err := cfg.GetEditor(editorName)
+		if err != nil {
+			return err
+		}
+
+		if editor.Name == cfg.DefaultEditorName {
+			return fmt.Errorf("%w: %s, set a different default editor first", ErrEditorIsDefault, editor.Name)
+		}
+
+		logger.Printf("removing editor: %s\n", editor.String())
+
+		available := []editors.Editor{}
+		for _, e := range cfg.AvailableEditors {
+			if e.Name != editor.Name {
+				available = append(available, e)
+			}
+		}
+
+		cfg.AvailableEditors = available
+		return nil
+	})
+}
+
+// checkEditor validates the editor and resolves the path of its executable,
+// which may be the name of a command in PATH.
+func checkEditor(editor editors.Editor) (editors.Editor, error) {
+	err := editor.Validate()
+	if err != nil {
+		return editor, err
+	}
+
+	switch editor.RunTypeOrDefault() {
+	case editors.RunTypeOpen, editors.RunTypeShell:
+		// The path is not used, or is resolved by the shell.
+		return editor, nil
+	}
+
+	if editor.ExecPath == "" {
+		return editor, fmt.Errorf("%w: no path configured for %s", ErrEditorNotInstalled, editor.Name)
+	}
+
+	path, err := exec.LookPath(editor.ExecPath)
+	if err != nil {
+		return editor, fmt.Errorf("%w: %s", ErrEditorNotInstalled, editor.ExecPath)
+	}
+
+	editor.ExecPath, err = filepath.Abs(path)
+	return editor, err
+}
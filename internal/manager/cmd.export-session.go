@@ -39,8 +39,13 @@ func (s *manager) ExportSession(ctx context.Context, arg ExportSessionArguments)
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
+	skip, err := ignoreRules(session.Location)
+	if err != nil {
+		return fmt.Errorf("failed to load ignore rules: %w", err)
+	}
+
 	// Copy the session to the export path
-	if err := copy.Copy(session.Location, absPath); err != nil {
+	if err := copy.Copy(session.Location, absPath, copy.Options{Skip: skip}); err != nil {
 		return fmt.Errorf("failed to copy session: %w", err)
 	}
 	logger.Printf("session exported successfully\n")
@@ -4,32 +4,47 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 
-	"github.com/go-git/go-git/v5"
 	"github.com/letstrygo/letstry/internal/logging"
 )
 
 var (
-	ErrInvalidSourceType = errors.New("invalid source type, must be repository")
+	ErrInvalidSourceType = errors.New("invalid source type, must be repository or archive")
 )
 
 type ImportTemplateArguments struct {
-	TemplateName  string
-	RepositoryUrl string
+	TemplateName string
+	// A git repository or archive.
+	Source     string
+	Repository RepositoryOptions
+	Archive    ArchiveOptions
 }
 
 func (s *manager) ImportTemplate(ctx context.Context, args ImportTemplateArguments) (Template, error) {
 	var zeroValue Template
 
-	sourceType, err := s.GetSessionSourceType(ctx, args.RepositoryUrl)
+	source, opts, err := s.parseSessionSource(ctx, args.Source)
 	if err != nil {
 		return zeroValue, err
 	}
 
-	if sourceType != SessionSourceTypeRepository {
+	if source.SourceType != SessionSourceTypeRepository && source.SourceType != SessionSourceTypeArchive {
 		return zeroValue, ErrInvalidSourceType
 	}
 
+	if source.SourceType != SessionSourceTypeRepository && !args.Repository.IsZero() {
+		return zeroValue, ErrRepositoryOptionsNotSupported
+	}
+
+	if source.SourceType != SessionSourceTypeArchive && !args.Archive.IsZero() {
+		return zeroValue, ErrArchiveOptionsNotSupported
+	}
+
+	// The source after resolving aliases and removing any explicit prefix.
+	args.Source = source.Value
+	args.Repository = opts.Merge(args.Repository)
+
 	template := Template(args.TemplateName)
 
 	if s.storage.DirectoryExists(template.StoragePath()) {
@@ -46,14 +61,41 @@ func (s *manager) ImportTemplate(ctx context.Context, args ImportTemplateArgumen
 		return "", err
 	}
 
-	logger.Printf("cloning repository %s\n", args.RepositoryUrl)
-	_, err = git.PlainClone(template.AbsolutePath(ctx), false, &git.CloneOptions{
-		URL: args.RepositoryUrl,
-	})
+	if source.SourceType == SessionSourceTypeArchive {
+		err = extractArchiveSource(ctx, args.Source, template.AbsolutePath(ctx), args.Archive)
+		if err != nil {
+			_ = os.RemoveAll(template.AbsolutePath(ctx))
+			return zeroValue, fmt.Errorf("failed to extract archive: %v", err)
+		}
+
+		err = s.commitTemplate(ctx, template, fmt.Sprintf("Import %s", args.Source))
+		if err != nil {
+			return zeroValue, err
+		}
+
+		logger.Printf("imported template: %s\n", template.FormattedString(ctx))
+		return template, nil
+	}
+
+	logger.Printf("cloning repository %s\n", args.Source)
+	err = s.cloneRepository(ctx, args.Source, template.AbsolutePath(ctx), args.Repository)
 	if err != nil {
+		_ = os.RemoveAll(template.AbsolutePath(ctx))
 		return zeroValue, fmt.Errorf("failed to clone repository: %v", err)
 	}
 
+	// The history of the template is recorded separately from the cloned
+	// repository, which is kept so that the template can be updated.
+	message := fmt.Sprintf("Import %s", args.Source)
+	if args.Repository.Subdir != "" {
+		message = fmt.Sprintf("Import %s from %s", args.Repository.Subdir, args.Source)
+	}
+
+	err = s.commitTemplate(ctx, template, message)
+	if err != nil {
+		return zeroValue, err
+	}
+
 	logger.Printf("imported template: %s\n", template.FormattedString(ctx))
 	return template, nil
 }
@@ -1,6 +1,9 @@
 package manager
 
-import "context"
+import (
+	"context"
+	"strings"
+)
 
 func (s *manager) ListTemplates(ctx context.Context) ([]Template, error) {
 	if !s.storage.DirectoryExists("templates") {
@@ -12,8 +15,13 @@ func (s *manager) ListTemplates(ctx context.Context) ([]Template, error) {
 		return nil, err
 	}
 
-	var result []Template
+	result := []Template{}
 	for _, t := range templates {
+		// Skip directories used while saving templates.
+		if strings.HasPrefix(t, ".") {
+			continue
+		}
+
 		result = append(result, Template(t))
 	}
 
@@ -2,7 +2,6 @@ package manager
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -99,54 +98,36 @@ func (s *manager) monitorDirectoryAccessible(path string, callback func() error)
 }
 
 func (s *manager) removeSession(ctx context.Context, id identifier.ID) error {
-	sessions, err := s.ListSessions(ctx)
-	if err != nil {
-		return err
-	}
-
-	for i, session := range sessions {
-		if session.ID == id {
-			// Remove the session
-			sessions = append(sessions[:i], sessions[i+1:]...)
-
-			file, err := s.storage.OpenFile("sessions.json")
-			if err != nil {
-				return fmt.Errorf("failed to open sessions file: %v", err)
-			}
-			defer file.Close()
-
-			data, err := json.MarshalIndent(sessions, "", "    ")
-			if err != nil {
-				return fmt.Errorf("failed to marshal sessions: %v", err)
-			}
-
-			err = file.Truncate(0)
-			if err != nil {
-				return fmt.Errorf("failed to truncate sessions file: %v", err)
-			}
+	var removed *Session
 
-			_, err = file.Write(data)
-			if err != nil {
-				return fmt.Errorf("failed to write sessions: %v", err)
+	err := s.updateSessions(ctx, func(sessions []Session) ([]Session, error) {
+		for i, session := range sessions {
+			if session.ID == id {
+				removed = &session
+				return append(sessions[:i], sessions[i+1:]...), nil
 			}
+		}
 
-			err = file.Sync()
-			if err != nil {
-				return fmt.Errorf("failed to sync sessions file: %v", err)
-			}
+		return nil, fmt.Errorf("session with id %s not found", id)
+	})
+	if err != nil {
+		return err
+	}
 
-			// Give the process manager time to settle
-			time.Sleep(1 * time.Second)
+	// Give the process manager time to settle
+	time.Sleep(1 * time.Second)
 
-			// Remove the temporary directory
-			err = os.RemoveAll(session.Location)
-			if err != nil {
-				return fmt.Errorf("failed to remove temporary directory: %v", err)
-			}
+	// Nothing to keep if the directory has already been removed.
+	if _, err := os.Stat(removed.Location); os.IsNotExist(err) {
+		return nil
+	}
 
-			return nil
-		}
+	// Move the temporary directory to the trash so that it can be restored
+	// if the session was closed by mistake.
+	err = s.trashSession(ctx, *removed)
+	if err != nil {
+		return fmt.Errorf("failed to remove temporary directory: %v", err)
 	}
 
-	return fmt.Errorf("session with id %s not found", id)
+	return nil
 }
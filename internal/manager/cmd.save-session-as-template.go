@@ -3,17 +3,26 @@ package manager
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/letstrygo/letstry/internal/logging"
+	"github.com/letstrygo/letstry/internal/util/prompt"
 	"github.com/otiai10/copy"
 )
 
 var (
-	ErrMissingTemplateName = errors.New("missing template name")
+	ErrMissingTemplateName    = errors.New("missing template name")
+	ErrTemplateNotOverwritten = errors.New("template not overwritten")
 )
 
 type SaveSessionAsTemplateArguments struct {
 	TemplateName string `json:"template_name"`
+	// Overwrite an existing template without asking for confirmation, even
+	// if the session was not created from it.
+	Overwrite bool `json:"overwrite"`
 }
 
 func (s *manager) SaveSessionAsTemplate(ctx context.Context, arg SaveSessionAsTemplateArguments) (Template, error) {
@@ -32,39 +41,121 @@ func (s *manager) SaveSessionAsTemplate(ctx context.Context, arg SaveSessionAsTe
 		return "", err
 	}
 
-	var template Template
+	var (
+		template Template
+		origin   Template
+	)
+
+	if session.Source.SourceType == SessionSourceTypeTemplate {
+		origin, _ = ParseTemplateReference(session.Source.Value)
+	}
 
 	if arg.TemplateName != "" {
 		template = Template(arg.TemplateName)
 	} else {
-		if session.Source.SourceType == SessionSourceTypeTemplate {
-			template = Template(session.Source.Value)
-		}
+		template = origin
 	}
 
 	if template == "" {
 		return "", ErrMissingTemplateName
 	}
 
-	if s.storage.DirectoryExists(template.StoragePath()) {
-		logger.Printf("template already exists, deleting template %s\n", template.String())
-		err = s.storage.DeleteDirectory(template.StoragePath())
+	exists := s.storage.DirectoryExists(template.StoragePath())
+
+	// Confirm before replacing a template the session was not created from.
+	if exists && template != origin && !arg.Overwrite {
+		confirmed, err := prompt.Confirm(fmt.Sprintf("template %s already exists and this session was not created from it, overwrite it?", template.String()))
 		if err != nil {
+			if errors.Is(err, prompt.ErrNotInteractive) {
+				return "", fmt.Errorf("template %s already exists, use --yes to overwrite it", template.String())
+			}
+
 			return "", err
 		}
+
+		if !confirmed {
+			return "", ErrTemplateNotOverwritten
+		}
 	}
 
-	logger.Printf("creating template %s from session %s\n", template.String(), session.ID.FormattedString())
+	logger.Printf("saving template %s from session %s\n", template.String(), session.ID.FormattedString())
 
-	err = s.storage.CreateDirectory(template.StoragePath())
+	templatesDir := s.storage.GetAbsolutePath("templates")
+	templateDir := template.AbsolutePath(ctx)
+
+	// The template is staged alongside the existing templates, and is only
+	// swapped in once it has been saved successfully.
+	stagingDir, err := os.MkdirTemp(templatesDir, fmt.Sprintf(".%s.staging-", template.String()))
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to stage template: %v", err)
 	}
+	defer os.RemoveAll(stagingDir)
+
+	// The templates history is kept so that the previous versions of the
+	// template remain available.
+	if exists {
+		history := filepath.Join(templateDir, templateHistoryDirectory)
+		if _, err := os.Stat(history); err == nil {
+			err = copy.Copy(history, filepath.Join(stagingDir, templateHistoryDirectory))
+			if err != nil {
+				return "", fmt.Errorf("failed to stage template: %v", err)
+			}
+		}
+	}
+
+	ignored, err := ignoreRules(session.Location)
+	if err != nil {
+		return "", fmt.Errorf("failed to load ignore rules: %v", err)
+	}
+
+	sessionHistory := filepath.Join(session.Location, templateHistoryDirectory)
+
+	err = copy.Copy(session.Location, stagingDir, copy.Options{
+		Skip: func(srcinfo os.FileInfo, src, dest string) (bool, error) {
+			// A history in the session would replace the templates own
+			// history, so it is never saved.
+			if srcinfo.IsDir() && src == sessionHistory {
+				return true, nil
+			}
 
-	err = copy.Copy(session.Location, template.AbsolutePath(ctx))
+			return ignored(srcinfo, src, dest)
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to stage template: %v", err)
+	}
+
+	err = commitTemplateDirectory(stagingDir, fmt.Sprintf("Save from session %s", session.ID))
 	if err != nil {
 		return "", err
 	}
 
+	err = swapDirectory(stagingDir, templateDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to save template: %v", err)
+	}
+
 	return template, nil
 }
+
+// swapDirectory replaces dest with src using renames, restoring dest if src
+// can not be moved into place.
+func swapDirectory(src string, dest string) error {
+	if _, err := os.Stat(dest); os.IsNotExist(err) {
+		return os.Rename(src, dest)
+	}
+
+	backup := fmt.Sprintf("%s.old-%d", src, time.Now().UnixNano())
+	err := os.Rename(dest, backup)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(src, dest)
+	if err != nil {
+		_ = os.Rename(backup, dest)
+		return err
+	}
+
+	return os.RemoveAll(backup)
+}
@@ -2,10 +2,8 @@ package manager
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 
-	"github.com/letstrygo/letstry/internal/logging"
 	"github.com/letstrygo/letstry/internal/util/identifier"
 )
 
@@ -43,52 +41,12 @@ func ParseSessionDisplayType(v string) (SessionDisplayType, error) {
 	return SessionDisplayTypeFull, ErrInvalidSessionDisplayType
 }
 
-type ShowSessionArguments struct {
-	SessionID  *identifier.ID
-	OutputType SessionDisplayType
-}
-
-func (m *manager) ShowSession(ctx context.Context, args ShowSessionArguments) error {
-	var (
-		session Session
-		err     error
-	)
-
-	switch {
-	case args.SessionID != nil:
-		// Try to load session from the ID.
-		session, err = m.GetSession(ctx, *args.SessionID)
-	default:
-		session, err = m.GetCurrentSession(ctx)
-	}
-	if err != nil {
-		return err
-	}
-
-	log, err := logging.LoggerFromContext(ctx)
-	if err != nil {
-		return err
-	}
-
-	switch args.OutputType {
-	case SessionDisplayTypeFull:
-		log.Printf("%v", session.String())
-	case SessionDisplayTypeLocation:
-		println(session.Location)
-	case SessionDisplayTypePID:
-		println(session.PID)
-	case SessionDisplayTypeEditor:
-		log.Printf("%v", session.Editor.FullString())
-	case SessionDisplayTypeJSON:
-		sessionData, err := json.MarshalIndent(session, "", "    ")
-		if err != nil {
-			return err
-		}
-
-		println(string(sessionData))
-	default:
-		return ErrInvalidSessionDisplayType
+// FindSession returns the session with the given ID, or the current session
+// if no ID is provided.
+func (m *manager) FindSession(ctx context.Context, id *identifier.ID) (Session, error) {
+	if id != nil {
+		return m.GetSession(ctx, *id)
 	}
 
-	return nil
+	return m.GetCurrentSession(ctx)
 }
@@ -35,6 +35,14 @@ func (m *manager) UpdateTemplate(ctx context.Context, arg UpdateTemplateArgument
 		return err
 	}
 
+	// Pulling removes every file that is not tracked by the repository, so
+	// the templates history is moved out of the way until it has finished.
+	restoreHistory, err := setAsideTemplateHistory(absPath)
+	if err != nil {
+		return err
+	}
+	defer restoreHistory()
+
 	err = w.Pull(&git.PullOptions{
 		Force: true,
 	})
@@ -42,5 +50,10 @@ func (m *manager) UpdateTemplate(ctx context.Context, arg UpdateTemplateArgument
 		return err
 	}
 
-	return nil
+	err = restoreHistory()
+	if err != nil {
+		return err
+	}
+
+	return m.commitTemplate(ctx, t, "Update from repository")
 }
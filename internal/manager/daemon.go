@@ -0,0 +1,506 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"github.com/letstrygo/letstry/internal/config/editors"
+	"github.com/letstrygo/letstry/internal/logging"
+	"github.com/letstrygo/letstry/internal/storage"
+	"github.com/letstrygo/letstry/internal/util/identifier"
+)
+
+const (
+	daemonSocketName   = "daemon.sock"
+	daemonLockName     = "daemon"
+	daemonPollInterval = 1 * time.Second
+	daemonIdleTimeout  = 5 * time.Minute
+	daemonStartTimeout = 5 * time.Second
+	daemonDialTimeout  = 1 * time.Second
+)
+
+var (
+	ErrDaemonAlreadyRunning = errors.New("daemon is already running")
+	ErrDaemonNotRunning     = errors.New("daemon is not running")
+	ErrDaemonConnectionLost = errors.New("lost connection to daemon")
+)
+
+type DaemonRequestType string
+
+const (
+	DaemonRequestTypeRegister DaemonRequestType = "register"
+	DaemonRequestTypeStatus   DaemonRequestType = "status"
+	DaemonRequestTypeStop     DaemonRequestType = "stop"
+)
+
+type DaemonRequest struct {
+	Type      DaemonRequestType `json:"type"`
+	SessionID identifier.ID     `json:"session_id,omitempty"`
+}
+
+type DaemonResponse struct {
+	Error    string          `json:"error,omitempty"`
+	Sessions []SessionStatus `json:"sessions,omitempty"`
+}
+
+type SessionState string
+
+func (s SessionState) String() string {
+	return string(s)
+}
+
+const (
+	// The session is waiting for its editors process capture delay to elapse.
+	SessionStatePending SessionState = "pending"
+	// The session is being monitored.
+	SessionStateMonitoring SessionState = "monitoring"
+)
+
+// SessionStatus describes the state of a session supervised by the daemon.
+type SessionStatus struct {
+	Session      Session      `json:"session"`
+	State        SessionState `json:"state"`
+	MonitorAfter time.Time    `json:"monitor_after"`
+}
+
+type daemon struct {
+	mgr    *manager
+	logger interface {
+		Printf(format string, v ...any)
+	}
+
+	listener net.Listener
+
+	mu       sync.Mutex
+	sessions map[identifier.ID]*SessionStatus
+	// Set once the daemon has decided to exit, after which sessions are no
+	// longer registered.
+	stopping bool
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// RunDaemon runs the supervisor daemon in the foreground. The daemon
+// monitors every session in the sessions file, removing them once their
+// editor has been closed, and accepts requests from other letstry processes
+// over a unix socket in the storage directory.
+func (s *manager) RunDaemon(ctx context.Context) error {
+	logger, err := logging.LoggerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	lock, err := s.storage.TryLock(daemonLockName)
+	if err != nil {
+		if errors.Is(err, storage.ErrLocked) {
+			return ErrDaemonAlreadyRunning
+		}
+
+		return err
+	}
+	defer lock.Unlock()
+
+	// Remove any socket left behind by a daemon that did not exit cleanly.
+	socketPath := s.storage.GetAbsolutePath(daemonSocketName)
+	_ = os.Remove(socketPath)
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", socketPath, err)
+	}
+	defer listener.Close()
+	defer os.Remove(socketPath)
+
+	// The daemon should outlive the terminal it was started from.
+	signal.Ignore(syscall.SIGHUP)
+
+	d := &daemon{
+		mgr:      s,
+		logger:   logger,
+		listener: listener,
+		sessions: map[identifier.ID]*SessionStatus{},
+		stop:     make(chan struct{}),
+	}
+
+	logger.Printf("daemon started, listening on %s\n", socketPath)
+
+	go d.serve(ctx)
+	d.supervise(ctx)
+
+	logger.Printf("daemon stopped\n")
+	return nil
+}
+
+func (d *daemon) shutdown() {
+	d.stopOnce.Do(func() {
+		d.mu.Lock()
+		d.stopping = true
+		d.mu.Unlock()
+
+		close(d.stop)
+
+		// Stop accepting connections straight away, so that other letstry
+		// processes start a new daemon rather than using this one.
+		_ = d.listener.Close()
+	})
+}
+
+func (d *daemon) serve(ctx context.Context) {
+	for {
+		conn, err := d.listener.Accept()
+		if err != nil {
+			select {
+			case <-d.stop:
+				return
+			default:
+			}
+
+			d.logger.Printf("failed to accept connection: %v\n", err)
+			continue
+		}
+
+		go d.handle(ctx, conn)
+	}
+}
+
+func (d *daemon) handle(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	var req DaemonRequest
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		d.logger.Printf("failed to decode request: %v\n", err)
+		return
+	}
+
+	var res DaemonResponse
+
+	switch req.Type {
+	case DaemonRequestTypeRegister:
+		if err := d.register(ctx, req.SessionID); err != nil {
+			res.Error = err.Error()
+		}
+	case DaemonRequestTypeStatus:
+		res.Sessions = d.status()
+	case DaemonRequestTypeStop:
+		d.shutdown()
+	default:
+		res.Error = fmt.Sprintf("unknown request type: %s", req.Type)
+	}
+
+	if err := json.NewEncoder(conn).Encode(res); err != nil {
+		d.logger.Printf("failed to encode response: %v\n", err)
+	}
+}
+
+// register starts supervising the session, honoring its editors process
+// capture delay.
+func (d *daemon) register(ctx context.Context, id identifier.ID) error {
+	session, err := d.mgr.GetSession(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stopping {
+		return ErrDaemonNotRunning
+	}
+
+	d.sessions[id] = &SessionStatus{
+		Session:      session,
+		State:        SessionStatePending,
+		MonitorAfter: time.Now().Add(session.Editor.ProcessCaptureDelay),
+	}
+
+	d.logger.Printf("registered session: %s\n", id)
+	return nil
+}
+
+func (d *daemon) status() []SessionStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	result := make([]SessionStatus, 0, len(d.sessions))
+	for _, status := range d.sessions {
+		result = append(result, *status)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].MonitorAfter.Before(result[j].MonitorAfter)
+	})
+
+	return result
+}
+
+// sync reconciles the supervised sessions with the sessions file. Sessions
+// that are no longer in the file are dropped, and sessions the daemon has
+// not yet seen are picked up.
+func (d *daemon) sync(ctx context.Context) error {
+	sessions, err := d.mgr.ListSessions(ctx)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	known := map[identifier.ID]bool{}
+	for _, session := range sessions {
+		// Sessions using the wait tracking type are removed by the process
+		// that is waiting for the editor to exit, and are only supervised in
+		// case that process exits first.
+		if session.Editor.TrackingTypeOrDefault() == editors.TrackingTypeWait && session.OwnerPID == 0 {
+			continue
+		}
+
+		known[session.ID] = true
+
+		if _, ok := d.sessions[session.ID]; !ok {
+			d.sessions[session.ID] = &SessionStatus{
+				Session:      session,
+				State:        SessionStatePending,
+				MonitorAfter: time.Now().Add(session.Editor.ProcessCaptureDelay),
+			}
+		}
+	}
+
+	for id := range d.sessions {
+		if !known[id] {
+			delete(d.sessions, id)
+		}
+	}
+
+	return nil
+}
+
+// due returns the sessions whose capture delay has elapsed.
+func (d *daemon) due() []Session {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+
+	var result []Session
+	for _, status := range d.sessions {
+		if now.Before(status.MonitorAfter) {
+			continue
+		}
+
+		status.State = SessionStateMonitoring
+		result = append(result, status.Session)
+	}
+
+	return result
+}
+
+func (d *daemon) supervise(ctx context.Context) {
+	ticker := time.NewTicker(daemonPollInterval)
+	defer ticker.Stop()
+
+	idleSince := time.Now()
+
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+		}
+
+		if err := d.sync(ctx); err != nil {
+			d.logger.Printf("failed to load sessions: %v\n", err)
+			continue
+		}
+
+		due := d.due()
+		closed := d.mgr.closedSessions(due)
+
+		for _, session := range due {
+			if !closed[session.ID] {
+				continue
+			}
+
+			switch session.Editor.TrackingType {
+			case editors.TrackingTypeFileAccess:
+				d.logger.Printf("cleaning up session: %s (directory no longer being accessed)\n", session.ID)
+			case editors.TrackingTypeProcess:
+				d.logger.Printf("cleaning up session: %s (process no longer running)\n", session.ID)
+			case editors.TrackingTypeWait:
+				d.logger.Printf("cleaning up session: %s (letstry process waiting for the editor no longer running)\n", session.ID)
+			}
+
+			if err := d.mgr.removeSession(ctx, session.ID); err != nil {
+				d.logger.Printf("failed to clean up session %s: %v\n", session.ID, err)
+				continue
+			}
+
+			d.mu.Lock()
+			delete(d.sessions, session.ID)
+			d.mu.Unlock()
+		}
+
+		// The decision to exit is made while holding the lock, so that a
+		// session can not be registered with a daemon that is exiting.
+		d.mu.Lock()
+		idle := len(d.sessions) == 0
+		expired := idle && time.Since(idleSince) > daemonIdleTimeout
+		if expired {
+			d.stopping = true
+		}
+		d.mu.Unlock()
+
+		if !idle {
+			idleSince = time.Now()
+		} else if expired {
+			d.logger.Printf("no sessions to supervise for %v, exiting\n", daemonIdleTimeout)
+			d.shutdown()
+		}
+	}
+}
+
+// closedSessions reports which of the given sessions are no longer being
+// used by their editor. Sessions tracked by file access are checked in a
+// single pass, and sessions using the wait tracking type are closed once the
+// process waiting for their editor has exited.
+func (s *manager) closedSessions(sessions []Session) map[identifier.ID]bool {
+	result := make(map[identifier.ID]bool, len(sessions))
+
+	fileAccessSessions := []Session{}
+	for _, session := range sessions {
+		switch session.Editor.TrackingType {
+		case editors.TrackingTypeProcess:
+			_, err := process.NewProcess(int32(session.PID))
+			result[session.ID] = err != nil
+		case editors.TrackingTypeWait:
+			_, err := process.NewProcess(int32(session.OwnerPID))
+			result[session.ID] = err != nil
+		case editors.TrackingTypeFileAccess:
+			fileAccessSessions = append(fileAccessSessions, session)
+		}
+	}
+
+	for id, active := range ActiveSessions(fileAccessSessions) {
+		result[id] = !active
+	}
+
+	return result
+}
+
+// daemonRequest sends a request to the running daemon.
+func (s *manager) daemonRequest(req DaemonRequest) (DaemonResponse, error) {
+	var res DaemonResponse
+
+	conn, err := net.DialTimeout("unix", s.storage.GetAbsolutePath(daemonSocketName), daemonDialTimeout)
+	if err != nil {
+		return res, ErrDaemonNotRunning
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return res, fmt.Errorf("%w: failed to send request: %v", ErrDaemonConnectionLost, err)
+	}
+
+	if err := json.NewDecoder(conn).Decode(&res); err != nil {
+		return res, fmt.Errorf("%w: failed to read response: %v", ErrDaemonConnectionLost, err)
+	}
+
+	// A daemon that is exiting reports that it is not running.
+	if res.Error == ErrDaemonNotRunning.Error() {
+		return res, ErrDaemonNotRunning
+	}
+
+	if res.Error != "" {
+		return res, errors.New(res.Error)
+	}
+
+	return res, nil
+}
+
+// ensureDaemon starts the daemon in the background if it is not running.
+func (s *manager) ensureDaemon(ctx context.Context) error {
+	if _, err := s.daemonRequest(DaemonRequest{Type: DaemonRequestTypeStatus}); err == nil {
+		return nil
+	}
+
+	logger, err := logging.LoggerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Call this application again, but start it in the background as it's own process.
+	// This will allow the user to continue using the current terminal session.
+	cmd := exec.Command(os.Args[0], "daemon")
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start daemon: %v", err)
+	}
+	logger.Printf("started daemon (pid %d)\n", cmd.Process.Pid)
+	_ = cmd.Process.Release()
+
+	deadline := time.Now().Add(daemonStartTimeout)
+	for time.Now().Before(deadline) {
+		if _, err := s.daemonRequest(DaemonRequest{Type: DaemonRequestTypeStatus}); err == nil {
+			return nil
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return fmt.Errorf("timed out waiting for daemon to start")
+}
+
+// RegisterSession hands the session to the daemon for supervision, starting
+// the daemon if required. The daemon may exit after being idle between being
+// found running and receiving the session, in which case a new daemon is
+// started and the session is registered again.
+func (s *manager) RegisterSession(ctx context.Context, id identifier.ID) error {
+	req := DaemonRequest{
+		Type:      DaemonRequestTypeRegister,
+		SessionID: id,
+	}
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if err := s.ensureDaemon(ctx); err != nil {
+			return err
+		}
+
+		_, err = s.daemonRequest(req)
+		if !errors.Is(err, ErrDaemonNotRunning) && !errors.Is(err, ErrDaemonConnectionLost) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// DaemonStatus returns the state of every session supervised by the daemon.
+func (s *manager) DaemonStatus(ctx context.Context) ([]SessionStatus, error) {
+	res, err := s.daemonRequest(DaemonRequest{Type: DaemonRequestTypeStatus})
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Sessions == nil {
+		return []SessionStatus{}, nil
+	}
+
+	return res.Sessions, nil
+}
+
+// StopDaemon asks the running daemon to exit.
+func (s *manager) StopDaemon(ctx context.Context) error {
+	_, err := s.daemonRequest(DaemonRequest{Type: DaemonRequestTypeStop})
+	return err
+}
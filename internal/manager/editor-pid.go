@@ -0,0 +1,202 @@
+package manager
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"github.com/letstrygo/letstry/internal/config/editors"
+)
+
+const (
+	pidResolutionTimeout      = 5 * time.Second
+	pidResolutionPollInterval = 250 * time.Millisecond
+)
+
+// locatePid attempts to locate the PID of the editor process responsible
+// for the session, using the PID resolution configured for the editor. The
+// search continues until the editors capture delay has elapsed, as launchers
+// such as code hand the session to another process and exit. If no better
+// candidate is found, the PID of the launched process is returned.
+func (s *manager) locatePid(editor editors.Editor, pid int, location string) (int, error) {
+	// The PID is only used when tracking the editors process.
+	if editor.TrackingTypeOrDefault() != editors.TrackingTypeProcess {
+		return pid, nil
+	}
+
+	resolution := editor.PIDResolutionOrDefault()
+	if resolution == editors.PIDResolutionLaunched {
+		return pid, nil
+	}
+
+	deadline := time.Now().Add(max(pidResolutionTimeout, editor.ProcessCaptureDelay))
+	for {
+		var (
+			found int
+			ok    bool
+		)
+
+		switch resolution {
+		case editors.PIDResolutionTree:
+			found, ok = newestDescendant(editor, pid)
+		case editors.PIDResolutionLocation:
+			found, ok = oldestReferencingProcess(editor, pid, location)
+		}
+
+		if ok {
+			return found, nil
+		}
+
+		if time.Now().After(deadline) {
+			return pid, nil
+		}
+
+		time.Sleep(pidResolutionPollInterval)
+	}
+}
+
+// descendants returns every process in the child-process tree of pid.
+func descendants(pid int) []*process.Process {
+	root, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return nil
+	}
+
+	var result []*process.Process
+
+	queue := []*process.Process{root}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		children, err := current.Children()
+		if err != nil {
+			continue
+		}
+
+		result = append(result, children...)
+		queue = append(queue, children...)
+	}
+
+	return result
+}
+
+func newestDescendant(editor editors.Editor, pid int) (int, bool) {
+	candidates := filterByName(editor, descendants(pid))
+	if len(candidates) < 1 {
+		return 0, false
+	}
+
+	sortByCreateTime(candidates)
+	return int(candidates[len(candidates)-1].Pid), true
+}
+
+func oldestReferencingProcess(editor editors.Editor, pid int, location string) (int, bool) {
+	locations := []string{filepath.Clean(location)}
+	if resolved, err := filepath.EvalSymlinks(location); err == nil && resolved != locations[0] {
+		locations = append(locations, resolved)
+	}
+
+	// Prefer the processes spawned by the launched process. The launched
+	// process itself is never a candidate, as it always references the
+	// location on its command line and is often a launcher that exits once
+	// the editor has started.
+	if found, ok := oldestReferencing(filterByName(editor, descendants(pid)), locations); ok {
+		return found, true
+	}
+
+	all, err := process.Processes()
+	if err != nil {
+		return 0, false
+	}
+
+	others := make([]*process.Process, 0, len(all))
+	for _, p := range all {
+		if int(p.Pid) != pid {
+			others = append(others, p)
+		}
+	}
+
+	return oldestReferencing(filterByName(editor, others), locations)
+}
+
+// oldestReferencing returns the oldest process whose command line references
+// one of the locations. If there are none, the oldest process whose working
+// directory is beneath one of the locations is returned instead.
+func oldestReferencing(procs []*process.Process, locations []string) (int, bool) {
+	var byCmdline, byCwd []*process.Process
+
+	for _, p := range procs {
+		if cmdline, err := p.Cmdline(); err == nil && containsAny(cmdline, locations) {
+			byCmdline = append(byCmdline, p)
+			continue
+		}
+
+		if cwd, err := p.Cwd(); err == nil && isUnderAny(cwd, locations) {
+			byCwd = append(byCwd, p)
+		}
+	}
+
+	for _, matches := range [][]*process.Process{byCmdline, byCwd} {
+		if len(matches) > 0 {
+			sortByCreateTime(matches)
+			return int(matches[0].Pid), true
+		}
+	}
+
+	return 0, false
+}
+
+func filterByName(editor editors.Editor, procs []*process.Process) []*process.Process {
+	if editor.ProcessName == "" {
+		return procs
+	}
+
+	var result []*process.Process
+	for _, p := range procs {
+		name, err := p.Name()
+		if err != nil {
+			continue
+		}
+
+		if strings.Contains(strings.ToLower(name), strings.ToLower(editor.ProcessName)) {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
+
+func sortByCreateTime(procs []*process.Process) {
+	createTimes := make(map[int32]int64, len(procs))
+	for _, p := range procs {
+		createTimes[p.Pid], _ = p.CreateTime()
+	}
+
+	sort.SliceStable(procs, func(i, j int) bool {
+		return createTimes[procs[i].Pid] < createTimes[procs[j].Pid]
+	})
+}
+
+func containsAny(value string, substrings []string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(value, substring) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isUnderAny(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+
+	return false
+}
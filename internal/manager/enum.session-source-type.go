@@ -3,16 +3,21 @@ package manager
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/fatih/color"
-	"github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/config"
+
+	"github.com/letstrygo/letstry/internal/config"
 )
 
 var (
-	ErrInvalidSessionSource = errors.New("invalid session source")
+	ErrInvalidSessionSource   = errors.New("invalid session source")
+	ErrAmbiguousSessionSource = errors.New("ambiguous session source")
 )
 
 type SessionSourceType string
@@ -29,37 +34,203 @@ const (
 	SessionSourceTypeRepository SessionSourceType = "repository"
 	SessionSourceTypeDirectory  SessionSourceType = "directory"
 	SessionSourceTypeTemplate   SessionSourceType = "template"
+	SessionSourceTypeArchive    SessionSourceType = "archive"
 	SessionSourceTypeBlank      SessionSourceType = "blank"
 )
 
+// SourcePrefix is an explicit prefix on a session source that selects its
+// type, skipping detection.
+type SourcePrefix string
+
+const (
+	SourcePrefixTemplate   SourcePrefix = "tpl:"
+	SourcePrefixDirectory  SourcePrefix = "dir:"
+	SourcePrefixRepository SourcePrefix = "git:"
+	SourcePrefixArchive    SourcePrefix = "archive:"
+)
+
+// sourcePrefixes maps each explicit prefix to the source type it selects.
+var sourcePrefixes = map[SourcePrefix]SessionSourceType{
+	SourcePrefixTemplate:   SessionSourceTypeTemplate,
+	SourcePrefixDirectory:  SessionSourceTypeDirectory,
+	SourcePrefixRepository: SessionSourceTypeRepository,
+	SourcePrefixArchive:    SessionSourceTypeArchive,
+}
+
+// prefixFor returns the explicit prefix that selects the source type.
+func prefixFor(sourceType SessionSourceType) SourcePrefix {
+	for prefix, t := range sourcePrefixes {
+		if t == sourceType {
+			return prefix
+		}
+	}
+
+	return ""
+}
+
+var (
+	// Schemes of URLs that refer to git repositories.
+	repositorySchemes = []string{"http", "https", "git", "ssh", "git+ssh", "ssh+git", "file"}
+	// The scp-like syntax used by git for ssh, for example
+	// git@github.com:org/repo.git or git@example.com:/srv/repo.git.
+	scpLikeRepositoryPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+@[A-Za-z0-9.-]+:([^/\\]|/[^/\\])`)
+)
+
+// looksLikeRepository returns true if the shape of value is that of a git
+// repository URL. No network access is performed.
+func looksLikeRepository(value string) bool {
+	if scpLikeRepositoryPattern.MatchString(value) {
+		return true
+	}
+
+	// Archives can be downloaded using http(s) URLs as well.
+	if isArchiveURL(value) {
+		return false
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" {
+		return false
+	}
+
+	for _, scheme := range repositorySchemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return u.Host != "" || u.Scheme == "file"
+		}
+	}
+
+	return false
+}
+
 // GetSessionSourceType returns the type of session source for the given value.
 func (s *manager) GetSessionSourceType(ctx context.Context, value string) (SessionSourceType, error) {
+	source, _, err := s.parseSessionSource(ctx, value)
+	if err != nil {
+		return "", err
+	}
+
+	return source.SourceType, nil
+}
+
+// parseSessionSource determines the type of the session source, after
+// applying the configured source aliases and rewrite rules. Aliases may also
+// provide the options used to clone a repository.
+func (s *manager) parseSessionSource(ctx context.Context, value string) (Source, RepositoryOptions, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return Source{}, RepositoryOptions{}, err
+	}
+
+	value, opts, err := resolveSourceRules(cfg.Sources, value)
+	if err != nil {
+		return Source{}, RepositoryOptions{}, err
+	}
+
+	source, err := s.detectSessionSource(ctx, value)
+	return source, opts, err
+}
+
+// detectSessionSource determines the type of the session source. A source
+// may start with an explicit prefix selecting its type, otherwise its type
+// is detected without network access. Sources matching more than one type
+// are rejected rather than silently picking one of them.
+func (s *manager) detectSessionSource(ctx context.Context, value string) (Source, error) {
 	// Check for blank value.
 	if value == "" {
-		return SessionSourceTypeBlank, nil
+		return Source{SessionSourceTypeBlank, value}, nil
 	}
 
-	// Check for template.
-	_, err := s.GetTemplate(ctx, value)
-	if err == nil {
-		return SessionSourceTypeTemplate, nil
+	for prefix, sourceType := range sourcePrefixes {
+		rest, ok := strings.CutPrefix(value, string(prefix))
+
+		// A prefix followed by "//" is the scheme of a URL, such as git://.
+		if !ok || strings.HasPrefix(rest, "//") {
+			continue
+		}
+
+		// Any value is accepted as a repository or archive, as they can only
+		// be verified by cloning or extracting them.
+		explicit := sourceType == SessionSourceTypeRepository || sourceType == SessionSourceTypeArchive
+		if !explicit && !s.isSessionSourceType(ctx, sourceType, rest) {
+			return Source{}, fmt.Errorf("%w: %s is not a %s", ErrInvalidSessionSource, rest, sourceType)
+		}
+
+		return Source{sourceType, rest}, nil
 	}
 
-	// Check if directory exists and is a directory.
-	if absPath, err := filepath.Abs(value); err == nil {
-		stat, err := os.Stat(absPath)
-		if err == nil && stat.IsDir() {
-			return SessionSourceTypeDirectory, nil
+	matches := []SessionSourceType{}
+	for _, sourceType := range []SessionSourceType{
+		SessionSourceTypeTemplate,
+		SessionSourceTypeDirectory,
+		SessionSourceTypeRepository,
+		SessionSourceTypeArchive,
+	} {
+		// Bare repositories are directories as well, but are cloned rather
+		// than copied unless the dir: prefix is used.
+		if sourceType == SessionSourceTypeDirectory && isBareRepository(value) {
+			continue
+		}
+
+		if s.isSessionSourceType(ctx, sourceType, value) {
+			matches = append(matches, sourceType)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return Source{}, fmt.Errorf("%w: %s is not a template, directory, repository URL or archive", ErrInvalidSessionSource, value)
+	case 1:
+		return Source{matches[0], value}, nil
+	}
+
+	interpretations := make([]string, 0, len(matches))
+	for _, sourceType := range matches {
+		interpretations = append(interpretations, fmt.Sprintf("%s (use %s%s)", sourceType, prefixFor(sourceType), value))
+	}
+
+	return Source{}, fmt.Errorf("%w: %s could be a %s", ErrAmbiguousSessionSource, value, strings.Join(interpretations, " or a "))
+}
+
+// isSessionSourceType returns true if value can be used as a source of the
+// given type.
+func (s *manager) isSessionSourceType(ctx context.Context, sourceType SessionSourceType, value string) bool {
+	switch sourceType {
+	case SessionSourceTypeTemplate:
+		// Templates may be used at a specific revision.
+		name, _ := ParseTemplateReference(value)
+		_, err := s.GetTemplate(ctx, name.String())
+		return err == nil
+	case SessionSourceTypeDirectory:
+		absPath, err := filepath.Abs(value)
+		if err != nil {
+			return false
 		}
+
+		stat, err := os.Stat(absPath)
+		return err == nil && stat.IsDir()
+	case SessionSourceTypeRepository:
+		return looksLikeRepository(value) || isBareRepository(value)
+	case SessionSourceTypeArchive:
+		return looksLikeArchive(value)
 	}
 
-	// Check for repository.
-	_, err = git.NewRemote(nil, &config.RemoteConfig{
-		URLs: []string{value},
-	}).List(&git.ListOptions{})
-	if err == nil {
-		return SessionSourceTypeRepository, nil
+	return false
+}
+
+// isBareRepository returns true if path is the directory of a bare git
+// repository.
+func isBareRepository(path string) bool {
+	head, err := os.Stat(filepath.Join(path, "HEAD"))
+	if err != nil || !head.Mode().IsRegular() {
+		return false
+	}
+
+	for _, name := range []string{"objects", "refs"} {
+		stat, err := os.Stat(filepath.Join(path, name))
+		if err != nil || !stat.IsDir() {
+			return false
+		}
 	}
 
-	return "", ErrInvalidSessionSource
+	return true
 }
@@ -0,0 +1,57 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLooksLikeRepository(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{value: "https://github.com/org/repo.git", want: true},
+		{value: "ssh://git@github.com/org/repo.git", want: true},
+		{value: "file:///srv/repo.git", want: true},
+		{value: "git@github.com:org/repo.git", want: true},
+		{value: "git@example.com:/srv/repo.git", want: true},
+		{value: "git@example.com://srv/repo.git", want: false},
+		{value: "https://example.com/archive.tar.gz", want: false},
+		{value: "./repo", want: false},
+		{value: "my-template", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := looksLikeRepository(tt.value); got != tt.want {
+				t.Errorf("looksLikeRepository(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsBareRepository(t *testing.T) {
+	bare := t.TempDir()
+	for _, dir := range []string{"objects", "refs"} {
+		if err := os.Mkdir(filepath.Join(bare, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(bare, "HEAD"), []byte("ref: refs/heads/main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plain := t.TempDir()
+	if err := os.Mkdir(filepath.Join(plain, "objects"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if !isBareRepository(bare) {
+		t.Errorf("isBareRepository(%q) = false, want true", bare)
+	}
+
+	if isBareRepository(plain) {
+		t.Errorf("isBareRepository(%q) = true, want false", plain)
+	}
+}
@@ -0,0 +1,128 @@
+package manager
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+
+	"github.com/letstrygo/letstry/internal/config"
+)
+
+const (
+	IgnoreFileName    = ".letstryignore"
+	gitignoreFileName = ".gitignore"
+)
+
+// copySkipFunc is the signature used by copy.Options.Skip.
+type copySkipFunc func(srcinfo os.FileInfo, src string, dest string) (bool, error)
+
+// ignoreRules returns a function reporting which files beneath root should
+// not be copied, based on the configured ignore patterns and the ignore files
+// found within root.
+func ignoreRules(root string) (copySkipFunc, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	matcher, err := loadIgnoreMatcher(root, cfg.Ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(srcinfo os.FileInfo, src string, dest string) (bool, error) {
+		rel, err := filepath.Rel(root, src)
+		if err != nil || rel == "." {
+			return false, nil
+		}
+
+		return matcher.Match(splitPath(rel), srcinfo.IsDir()), nil
+	}, nil
+}
+
+func loadIgnoreMatcher(root string, cfg config.IgnoreConfig) (gitignore.Matcher, error) {
+	patterns := []gitignore.Pattern{}
+	for _, pattern := range cfg.Patterns {
+		patterns = append(patterns, gitignore.ParsePattern(pattern, nil))
+	}
+
+	// Ignore files later in the list take precedence.
+	fileNames := []string{IgnoreFileName}
+	if cfg.UseGitignore {
+		fileNames = []string{gitignoreFileName, IgnoreFileName}
+	}
+
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		var domain []string
+		if rel != "." {
+			domain = splitPath(rel)
+
+			// Don't look for ignore files in directories that are ignored.
+			if d.Name() == ".git" || gitignore.NewMatcher(patterns).Match(domain, true) {
+				return filepath.SkipDir
+			}
+		}
+
+		for _, fileName := range fileNames {
+			filePatterns, err := readIgnoreFile(filepath.Join(path, fileName), domain)
+			if err != nil {
+				return err
+			}
+
+			patterns = append(patterns, filePatterns...)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return gitignore.NewMatcher(patterns), nil
+}
+
+func readIgnoreFile(path string, domain []string) ([]gitignore.Pattern, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	defer f.Close()
+
+	var patterns []gitignore.Pattern
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		patterns = append(patterns, gitignore.ParsePattern(line, domain))
+	}
+
+	return patterns, scanner.Err()
+}
+
+func splitPath(path string) []string {
+	return strings.Split(filepath.ToSlash(path), "/")
+}
@@ -0,0 +1,276 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/otiai10/copy"
+
+	"github.com/letstrygo/letstry/internal/logging"
+)
+
+var (
+	ErrRepositoryOptionsNotSupported = errors.New("repository options can only be used with git repository sources")
+	ErrInvalidSubdirectory           = errors.New("invalid subdirectory")
+)
+
+var commitHashPattern = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+
+// RepositoryOptions control how a git repository is cloned.
+type RepositoryOptions struct {
+	// A branch, tag or commit SHA to check out. Defaults to the default
+	// branch of the repository.
+	Ref string `json:"ref,omitempty"`
+	// When greater than zero, the history is truncated to this many commits.
+	Depth int `json:"depth,omitempty"`
+	// Clone the submodules of the repository as well.
+	RecurseSubmodules bool `json:"recurse_submodules,omitempty"`
+	// Only this directory of the repository is used. It's contents are
+	// copied without any repository information.
+	Subdir string `json:"subdir,omitempty"`
+}
+
+// IsZero returns true if none of the options have been set.
+func (o RepositoryOptions) IsZero() bool {
+	return o == RepositoryOptions{}
+}
+
+// Merge returns the options with any options set in override taking
+// precedence.
+func (o RepositoryOptions) Merge(override RepositoryOptions) RepositoryOptions {
+	if override.Ref != "" {
+		o.Ref = override.Ref
+	}
+
+	if override.Depth > 0 {
+		o.Depth = override.Depth
+	}
+
+	if override.RecurseSubmodules {
+		o.RecurseSubmodules = true
+	}
+
+	if override.Subdir != "" {
+		o.Subdir = override.Subdir
+	}
+
+	return o
+}
+
+// cloneRepository clones the repository at url into dest using the given
+// options.
+func (s *manager) cloneRepository(ctx context.Context, url string, dest string, opts RepositoryOptions) error {
+	if opts.Subdir == "" {
+		return s.clone(ctx, url, dest, opts)
+	}
+
+	subdir := filepath.Clean(filepath.FromSlash(opts.Subdir))
+	if filepath.IsAbs(subdir) || subdir == "." || subdir == ".." || strings.HasPrefix(subdir, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%w: %s", ErrInvalidSubdirectory, opts.Subdir)
+	}
+
+	cloneDir, err := os.MkdirTemp("", "letstry-clone")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(cloneDir)
+
+	err = s.clone(ctx, url, cloneDir, opts)
+	if err != nil {
+		return err
+	}
+
+	src := filepath.Join(cloneDir, subdir)
+	if stat, err := os.Stat(src); err != nil || !stat.IsDir() {
+		return fmt.Errorf("%w: %s does not exist in %s", ErrInvalidSubdirectory, opts.Subdir, url)
+	}
+
+	return copy.Copy(src, dest)
+}
+
+// clone clones the repository at url into dest, using the local mirror of the
+// repository when the cache is enabled. The origin remote of the clone always
+// points at url.
+func (s *manager) clone(ctx context.Context, url string, dest string, opts RepositoryOptions) error {
+	logger, err := logging.LoggerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var repo *git.Repository
+
+	if mirror, ok := s.cachedMirror(ctx, url, opts); ok {
+		repo, err = plainClone(ctx, mirror, dest, opts)
+		if err == nil {
+			err = setOriginURL(repo, url)
+		}
+
+		if err != nil {
+			logger.Printf("failed to clone from cache, cloning %s: %v\n", url, err)
+			repo = nil
+
+			if err := clearDirectory(dest); err != nil {
+				return err
+			}
+		}
+	}
+
+	if repo == nil {
+		repo, err = plainClone(ctx, url, dest, opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !opts.RecurseSubmodules {
+		return nil
+	}
+
+	// Submodules are updated once the origin remote points at url, so that
+	// relative submodule URLs are resolved against the original repository.
+	w, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	submodules, err := w.Submodules()
+	if err != nil {
+		return err
+	}
+
+	err = submodules.UpdateContext(ctx, &git.SubmoduleUpdateOptions{
+		Init:              true,
+		RecurseSubmodules: git.DefaultSubmoduleRecursionDepth,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update submodules: %v", err)
+	}
+
+	return nil
+}
+
+// setOriginURL points the origin remote of repo at url.
+func setOriginURL(repo *git.Repository, url string) error {
+	cfg, err := repo.Config()
+	if err != nil {
+		return err
+	}
+
+	origin, ok := cfg.Remotes[git.DefaultRemoteName]
+	if !ok {
+		return fmt.Errorf("repository has no %s remote", git.DefaultRemoteName)
+	}
+
+	origin.URLs = []string{url}
+	return repo.SetConfig(cfg)
+}
+
+// plainClone clones the repository at url into dest, checking out the
+// configured ref. Submodules are not cloned.
+func plainClone(ctx context.Context, url string, dest string, opts RepositoryOptions) (*git.Repository, error) {
+	logger, err := logging.LoggerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cloneOptions := &git.CloneOptions{
+		URL:   url,
+		Depth: opts.Depth,
+	}
+
+	if opts.Ref == "" {
+		return git.PlainCloneContext(ctx, dest, false, cloneOptions)
+	}
+
+	// Branches and tags can be cloned directly.
+	for _, name := range []plumbing.ReferenceName{
+		plumbing.NewBranchReferenceName(opts.Ref),
+		plumbing.NewTagReferenceName(opts.Ref),
+	} {
+		cloneOptions.ReferenceName = name
+		cloneOptions.SingleBranch = true
+
+		repo, err := git.PlainCloneContext(ctx, dest, false, cloneOptions)
+		if err == nil {
+			return repo, nil
+		}
+
+		if !isReferenceNotFound(err) {
+			return nil, err
+		}
+
+		// Remove anything left behind by the failed clone.
+		if err := clearDirectory(dest); err != nil {
+			return nil, err
+		}
+	}
+
+	if !commitHashPattern.MatchString(opts.Ref) {
+		return nil, fmt.Errorf("unknown ref %s", opts.Ref)
+	}
+
+	// Arbitrary commits can not be fetched from a shallow clone, so the full
+	// history is cloned before checking out the commit.
+	if opts.Depth > 0 {
+		logger.Printf("ignoring depth, %s is a commit\n", opts.Ref)
+	}
+
+	cloneOptions.ReferenceName = ""
+	cloneOptions.SingleBranch = false
+	cloneOptions.Depth = 0
+
+	repo, err := git.PlainCloneContext(ctx, dest, false, cloneOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(opts.Ref))
+	if err != nil {
+		return nil, fmt.Errorf("unknown ref %s: %v", opts.Ref, err)
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.Checkout(&git.CheckoutOptions{Hash: *hash})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check out %s: %v", opts.Ref, err)
+	}
+
+	return repo, nil
+}
+
+func isReferenceNotFound(err error) bool {
+	var noMatchingRef git.NoMatchingRefSpecError
+	return errors.Is(err, plumbing.ErrReferenceNotFound) || errors.As(err, &noMatchingRef)
+}
+
+// clearDirectory removes the contents of dir, leaving dir itself in place.
+func clearDirectory(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, entry := range entries {
+		err := os.RemoveAll(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
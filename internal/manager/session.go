@@ -30,6 +30,8 @@ func (s Source) FormattedValue() string {
 	switch s.SourceType {
 	case SessionSourceTypeDirectory:
 		fallthrough
+	case SessionSourceTypeArchive:
+		fallthrough
 	case SessionSourceTypeRepository:
 		colorWrapper = color.HiBlueString
 	case SessionSourceTypeTemplate:
@@ -49,8 +51,12 @@ func (s Source) ShortValue() string {
 		segments := strings.Split(s.Value, "/")
 		last := segments[len(segments)-1]
 		return strings.Replace(last, ".git", "", -1)
+	case SessionSourceTypeArchive:
+		segments := strings.Split(s.Value, "/")
+		return trimArchiveExtension(segments[len(segments)-1])
 	case SessionSourceTypeTemplate:
-		return s.Value
+		name, _ := ParseTemplateReference(s.Value)
+		return name.String()
 	default:
 		return "project"
 	}
@@ -62,12 +68,35 @@ type Session struct {
 	PID      int            `json:"pid"`
 	Source   Source         `json:"source"`
 	Editor   editors.Editor `json:"editor"`
+	// Values for the variables declared by the sessions template.
+	Variables map[string]string `json:"variables,omitempty"`
+	// The PID of the letstry process waiting for the editor to exit, for
+	// sessions using the wait tracking type.
+	OwnerPID int `json:"owner_pid,omitempty"`
 }
 
 func (s *Session) IsActive() bool {
 	return access.IsPathUse(s.Location)
 }
 
+// ActiveSessions reports whether each of the given sessions is still being
+// accessed, checking every session location in a single pass.
+func ActiveSessions(sessions []Session) map[identifier.ID]bool {
+	locations := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		locations = append(locations, session.Location)
+	}
+
+	inUse := access.PathsInUse(locations)
+
+	result := make(map[identifier.ID]bool, len(sessions))
+	for _, session := range sessions {
+		result[session.ID] = inUse[session.Location]
+	}
+
+	return result
+}
+
 func (s *Session) String() string {
 	src := s.Source.FormattedValue()
 	id := s.ID.FormattedString()
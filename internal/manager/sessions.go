@@ -2,9 +2,12 @@ package manager
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/letstrygo/letstry/internal/environment"
 	"github.com/letstrygo/letstry/internal/util/identifier"
 )
 
@@ -29,8 +32,17 @@ func (s *manager) GetSession(ctx context.Context, id identifier.ID) (Session, er
 	return Session{}, fmt.Errorf("session with ID %s not found", id)
 }
 
-// GetCurrentSession returns the session for the current working directory
+// GetCurrentSession returns the session for the current working directory.
+// When letstry is run from within a session's editor, the session ID set in
+// the environment takes precedence.
 func (s *manager) GetCurrentSession(ctx context.Context) (Session, error) {
+	if appEnvironment, err := environment.EnvironmentFromContext(ctx); err == nil && appEnvironment.SessionID != "" {
+		sess, err := s.GetSession(ctx, identifier.ID(appEnvironment.SessionID))
+		if err == nil {
+			return sess, nil
+		}
+	}
+
 	cwd, err := os.Getwd()
 	if err != nil {
 		return Session{}, fmt.Errorf("failed to get current working directory: %v", err)
@@ -44,11 +56,51 @@ func (s *manager) GetCurrentSession(ctx context.Context) (Session, error) {
 	return sess, err
 }
 
-// GetSessionForPath returns the session for the given path
+// GetSessionForPath returns the session containing the given path. The path
+// may be the session directory itself or any directory beneath it, and
+// symlinks are evaluated before comparing paths.
 func (s *manager) GetSessionForPath(ctx context.Context, path string) (Session, error) {
-	return s.GetSessionForPredicate(ctx, func(sess Session) bool {
-		return sess.Location == path
-	})
+	sessions, err := s.ListSessions(ctx)
+	if err != nil {
+		return Session{}, err
+	}
+
+	locations := make(map[string]Session, len(sessions))
+	for _, sess := range sessions {
+		locations[resolvePath(sess.Location)] = sess
+	}
+
+	// Walk up the parent directories until we find a session.
+	dir := resolvePath(path)
+	for {
+		if sess, ok := locations[dir]; ok {
+			return sess, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+
+		dir = parent
+	}
+
+	return Session{}, ErrSessionNotFound
+}
+
+// resolvePath returns the absolute path with all symlinks evaluated. If the
+// path cannot be evaluated, the cleaned absolute path is returned instead.
+func resolvePath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = filepath.Clean(path)
+	}
+
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved
+	}
+
+	return abs
 }
 
 // GetSessionForPredicate returns the session that matches the given predicate
@@ -68,3 +120,26 @@ func (s *manager) GetSessionForPredicate(ctx context.Context, predicate func(Ses
 
 	return Session{}, ErrSessionNotFound
 }
+
+// updateSessions performs a locked read-modify-write of the sessions file,
+// atomically replacing it with the sessions returned by update.
+func (s *manager) updateSessions(ctx context.Context, update func([]Session) ([]Session, error)) error {
+	return s.storage.Update("sessions.json", []byte("[]"), func(data []byte) ([]byte, error) {
+		sessions := make([]Session, 0)
+		if err := json.Unmarshal(data, &sessions); err != nil {
+			return nil, fmt.Errorf("failed to decode sessions file: %v", err)
+		}
+
+		sessions, err := update(sessions)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err = json.MarshalIndent(sessions, "", "    ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal sessions: %v", err)
+		}
+
+		return data, nil
+	})
+}
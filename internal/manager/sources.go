@@ -0,0 +1,139 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/letstrygo/letstry/internal/config"
+)
+
+const (
+	// The placeholder in a rewrite rule that is replaced with the remainder
+	// of the source.
+	sourceRewritePlaceholder = "{path}"
+)
+
+var (
+	ErrInvalidSourceRule = errors.New("invalid source rule")
+)
+
+type SourceRuleType string
+
+func (t SourceRuleType) String() string {
+	return string(t)
+}
+
+const (
+	SourceRuleTypeAlias   SourceRuleType = "alias"
+	SourceRuleTypeRewrite SourceRuleType = "rewrite"
+)
+
+// SourceRule is an alias or rewrite rule configured for session sources.
+type SourceRule struct {
+	Type SourceRuleType `json:"type"`
+	// The alias name, or the prefix of the rewrite rule.
+	Match  string `json:"match"`
+	Source string `json:"source"`
+	Ref    string `json:"ref,omitempty"`
+	Subdir string `json:"subdir,omitempty"`
+}
+
+func (r SourceRule) String() string {
+	value := fmt.Sprintf("%s=%s, source=%s", r.Type, color.YellowString(r.Match), r.Source)
+	if r.Ref != "" {
+		value = fmt.Sprintf("%s, ref=%s", value, r.Ref)
+	}
+
+	if r.Subdir != "" {
+		value = fmt.Sprintf("%s, subdir=%s", value, r.Subdir)
+	}
+
+	return value
+}
+
+// ListSourceRules returns the configured source aliases followed by the
+// rewrite rules.
+func (s *manager) ListSourceRules(ctx context.Context) ([]SourceRule, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []SourceRule{}
+	for _, alias := range cfg.Sources.Aliases {
+		rules = append(rules, SourceRule{
+			Type:   SourceRuleTypeAlias,
+			Match:  alias.Name,
+			Source: alias.Source,
+			Ref:    alias.Ref,
+			Subdir: alias.Subdir,
+		})
+	}
+
+	for _, rewrite := range cfg.Sources.Rewrites {
+		rules = append(rules, SourceRule{
+			Type:   SourceRuleTypeRewrite,
+			Match:  rewrite.Prefix,
+			Source: rewrite.Source,
+		})
+	}
+
+	return rules, nil
+}
+
+// ResolveSource returns the session source that value refers to after
+// applying the configured aliases and rewrite rules, along with the type of
+// the source.
+func (s *manager) ResolveSource(ctx context.Context, value string) (Source, RepositoryOptions, error) {
+	return s.parseSessionSource(ctx, value)
+}
+
+// resolveSourceRules applies the configured alias, then the longest matching
+// rewrite rule, to value. Values that match no rule are returned unchanged.
+func resolveSourceRules(sources config.SourcesConfig, value string) (string, RepositoryOptions, error) {
+	var opts RepositoryOptions
+
+	for _, alias := range sources.Aliases {
+		if alias.Name == "" || alias.Name != value {
+			continue
+		}
+
+		if alias.Source == "" {
+			return "", opts, fmt.Errorf("%w: alias %s has no source", ErrInvalidSourceRule, alias.Name)
+		}
+
+		value = alias.Source
+		opts = RepositoryOptions{Ref: alias.Ref, Subdir: alias.Subdir}
+		break
+	}
+
+	var rewrite *config.SourceRewrite
+	for i, candidate := range sources.Rewrites {
+		if candidate.Prefix == "" || !strings.HasPrefix(value, candidate.Prefix) {
+			continue
+		}
+
+		if rewrite == nil || len(candidate.Prefix) > len(rewrite.Prefix) {
+			rewrite = &sources.Rewrites[i]
+		}
+	}
+
+	if rewrite == nil {
+		return value, opts, nil
+	}
+
+	if _, ok := sourcePrefixes[SourcePrefix(rewrite.Prefix)]; ok {
+		return "", opts, fmt.Errorf("%w: %s is reserved and can not be rewritten", ErrInvalidSourceRule, rewrite.Prefix)
+	}
+
+	rest := strings.TrimPrefix(value, rewrite.Prefix)
+	if !strings.Contains(rewrite.Source, sourceRewritePlaceholder) {
+		return rewrite.Source + rest, opts, nil
+	}
+
+	return strings.ReplaceAll(rewrite.Source, sourceRewritePlaceholder, rest), opts, nil
+}
@@ -0,0 +1,460 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+const (
+	templateCommitAuthorName  = "letstry"
+	templateCommitAuthorEmail = "letstry@localhost"
+
+	// The git directory, within the template, that records the templates
+	// history. It is kept separate from any .git directory in the template,
+	// such as the repository a template was imported from, so that the
+	// history is only ever written to by letstry.
+	templateHistoryDirectory = ".letstry-history"
+)
+
+var (
+	ErrTemplateHasNoHistory = errors.New("template has no history")
+)
+
+// TemplateRevision is a single version of a template.
+type TemplateRevision struct {
+	Hash    string    `json:"hash"`
+	Message string    `json:"message"`
+	Author  string    `json:"author"`
+	Date    time.Time `json:"date"`
+}
+
+func (r TemplateRevision) ShortHash() string {
+	if len(r.Hash) < 7 {
+		return r.Hash
+	}
+
+	return r.Hash[:7]
+}
+
+func (r TemplateRevision) String() string {
+	date := color.BlueString("(%s)", r.Date.Format("2006-01-02 15:04:05"))
+	return fmt.Sprintf("rev=%s, date=%s, message=%s", color.YellowString(r.ShortHash()), date, r.Message)
+}
+
+func newTemplateRevision(commit *object.Commit) TemplateRevision {
+	return TemplateRevision{
+		Hash:    commit.Hash.String(),
+		Message: strings.TrimSpace(commit.Message),
+		Author:  commit.Author.Name,
+		Date:    commit.Author.When,
+	}
+}
+
+// ParseTemplateReference splits a template reference of the form name@rev
+// into the name of the template and the revision. The revision is empty if
+// the reference does not include one.
+func ParseTemplateReference(value string) (Template, string) {
+	name, rev, _ := strings.Cut(value, "@")
+	return Template(name), rev
+}
+
+// commitTemplate records the current contents of the template as a new
+// revision, initializing the templates history if required. No revision is
+// recorded if the template has not changed.
+func (s *manager) commitTemplate(ctx context.Context, t Template, message string) error {
+	return commitTemplateDirectory(t.AbsolutePath(ctx), message)
+}
+
+func commitTemplateDirectory(path string, message string) error {
+	repo, err := openTemplateHistory(path, true)
+	if err != nil {
+		return fmt.Errorf("failed to open template history: %v", err)
+	}
+
+	err = excludeTemplateHistory(path)
+	if err != nil {
+		return err
+	}
+
+	w, err := templateHistoryWorktree(repo)
+	if err != nil {
+		return err
+	}
+
+	err = w.AddWithOptions(&git.AddOptions{All: true})
+	if err != nil {
+		return fmt.Errorf("failed to stage template: %v", err)
+	}
+
+	_, err = w.Commit(message, &git.CommitOptions{
+		All: true,
+		Author: &object.Signature{
+			Name:  templateCommitAuthorName,
+			Email: templateCommitAuthorEmail,
+			When:  time.Now(),
+		},
+	})
+	if err != nil && !errors.Is(err, git.ErrEmptyCommit) {
+		return fmt.Errorf("failed to commit template: %v", err)
+	}
+
+	return nil
+}
+
+// openTemplateHistory opens the repository recording the history of the
+// template directory at path, using the directory as its worktree. The
+// repository is created if it does not exist and create is set.
+func openTemplateHistory(path string, create bool) (*git.Repository, error) {
+	storer := filesystem.NewStorage(osfs.New(filepath.Join(path, templateHistoryDirectory)), cache.NewObjectLRUDefault())
+	worktree := osfs.New(path)
+
+	repo, err := git.Open(storer, worktree)
+	if !errors.Is(err, git.ErrRepositoryNotExists) || !create {
+		return repo, err
+	}
+
+	// The repository is created without a worktree, as go-git would
+	// otherwise write a .git file linking the worktree to the repository.
+	_, err = git.Init(storer, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return git.Open(storer, worktree)
+}
+
+// excludeTemplateHistory hides the templates history from the repository
+// the template was cloned from, if there is one.
+func excludeTemplateHistory(path string) error {
+	info := filepath.Join(path, ".git", "info")
+	if stat, err := os.Stat(filepath.Dir(info)); err != nil || !stat.IsDir() {
+		return nil
+	}
+
+	exclude := filepath.Join(info, "exclude")
+	pattern := "/" + templateHistoryDirectory
+
+	data, err := os.ReadFile(exclude)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == pattern {
+			return nil
+		}
+	}
+
+	err = os.MkdirAll(info, 0755)
+	if err != nil {
+		return err
+	}
+
+	if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+		pattern = "\n" + pattern
+	}
+
+	file, err := os.OpenFile(exclude, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintln(file, pattern)
+	return err
+}
+
+// setAsideTemplateHistory moves the history of the template directory at
+// path next to the template, returning a function that moves it back. The
+// returned function can be called more than once.
+func setAsideTemplateHistory(path string) (func() error, error) {
+	history := filepath.Join(path, templateHistoryDirectory)
+	if _, err := os.Stat(history); os.IsNotExist(err) {
+		return func() error { return nil }, nil
+	}
+
+	aside := filepath.Join(filepath.Dir(path), fmt.Sprintf(".%s.history-%d", filepath.Base(path), time.Now().UnixNano()))
+	err := os.Rename(history, aside)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set aside template history: %v", err)
+	}
+
+	restored := false
+	return func() error {
+		if restored {
+			return nil
+		}
+
+		restored = true
+		return os.Rename(aside, history)
+	}, nil
+}
+
+// templateHistoryWorktree returns the worktree of the templates history,
+// excluding the history itself.
+func templateHistoryWorktree(repo *git.Repository) (*git.Worktree, error) {
+	w, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	w.Excludes = append(w.Excludes, gitignore.ParsePattern("/"+templateHistoryDirectory, nil))
+	return w, nil
+}
+
+// isTemplateRepository returns true if name is the name of a directory that
+// holds repository information rather than template contents.
+func isTemplateRepository(name string) bool {
+	return name == ".git" || name == templateHistoryDirectory
+}
+
+func (s *manager) openTemplateRepository(ctx context.Context, t Template) (*git.Repository, error) {
+	repo, err := openTemplateHistory(t.AbsolutePath(ctx), false)
+	if err != nil {
+		if errors.Is(err, git.ErrRepositoryNotExists) {
+			return nil, fmt.Errorf("%w: %s", ErrTemplateHasNoHistory, t.String())
+		}
+
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func resolveTemplateRevision(repo *git.Repository, rev string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("unknown revision %s: %v", rev, err)
+	}
+
+	return repo.CommitObject(*hash)
+}
+
+// TemplateHistory returns the revisions of the template, newest first.
+func (s *manager) TemplateHistory(ctx context.Context, name string) ([]TemplateRevision, error) {
+	t, err := s.GetTemplate(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := s.openTemplateRepository(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := repo.Log(&git.LogOptions{})
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return []TemplateRevision{}, nil
+		}
+
+		return nil, err
+	}
+
+	revisions := []TemplateRevision{}
+	err = commits.ForEach(func(commit *object.Commit) error {
+		revisions = append(revisions, newTemplateRevision(commit))
+		return nil
+	})
+
+	return revisions, err
+}
+
+// RollbackTemplate restores the template to the contents it had at the given
+// revision. The rollback is recorded as a new revision, so the revisions
+// that followed it remain in the templates history.
+func (s *manager) RollbackTemplate(ctx context.Context, name string, rev string) (TemplateRevision, error) {
+	t, err := s.GetTemplate(ctx, name)
+	if err != nil {
+		return TemplateRevision{}, err
+	}
+
+	repo, err := s.openTemplateRepository(ctx, t)
+	if err != nil {
+		return TemplateRevision{}, err
+	}
+
+	target, err := resolveTemplateRevision(repo, rev)
+	if err != nil {
+		return TemplateRevision{}, err
+	}
+
+	// Record any changes made to the template outside of letstry so that
+	// they are not lost.
+	err = s.commitTemplate(ctx, t, "Record changes before rollback")
+	if err != nil {
+		return TemplateRevision{}, err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return TemplateRevision{}, err
+	}
+
+	current, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return TemplateRevision{}, err
+	}
+
+	// Restore the contents of the revision on top of the existing history.
+	// Files that are not recorded in the history, such as ignored files, are
+	// left in place.
+	err = restoreTemplateRevision(current, target, t.AbsolutePath(ctx))
+	if err != nil {
+		return TemplateRevision{}, fmt.Errorf("failed to restore revision %s: %v", rev, err)
+	}
+
+	revision := newTemplateRevision(target)
+
+	err = s.commitTemplate(ctx, t, fmt.Sprintf("Roll back to %s", revision.ShortHash()))
+	if err != nil {
+		return TemplateRevision{}, err
+	}
+
+	return revision, nil
+}
+
+// exportTemplateRevision writes the contents the template had at the given
+// revision to dest.
+func (s *manager) exportTemplateRevision(ctx context.Context, t Template, rev string, dest string) error {
+	repo, err := s.openTemplateRepository(ctx, t)
+	if err != nil {
+		return err
+	}
+
+	commit, err := resolveTemplateRevision(repo, rev)
+	if err != nil {
+		return err
+	}
+
+	return writeTemplateRevision(commit, dest)
+}
+
+// restoreTemplateRevision replaces the files of the current revision in dest
+// with the files of the target revision.
+func restoreTemplateRevision(current *object.Commit, target *object.Commit, dest string) error {
+	files, err := current.Files()
+	if err != nil {
+		return err
+	}
+
+	err = files.ForEach(func(file *object.File) error {
+		if _, err := target.File(file.Name); err == nil {
+			return nil
+		}
+
+		err := os.Remove(filepath.Join(dest, filepath.FromSlash(file.Name)))
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return writeTemplateRevision(target, dest)
+}
+
+// writeTemplateRevision writes the files of the revision to dest, replacing
+// any existing files.
+func writeTemplateRevision(commit *object.Commit, dest string) error {
+	files, err := commit.Files()
+	if err != nil {
+		return err
+	}
+
+	return files.ForEach(func(file *object.File) error {
+		path := filepath.Join(dest, filepath.FromSlash(file.Name))
+
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			return err
+		}
+
+		contents, err := file.Reader()
+		if err != nil {
+			return err
+		}
+		defer contents.Close()
+
+		if file.Mode == filemode.Symlink {
+			target, err := io.ReadAll(contents)
+			if err != nil {
+				return err
+			}
+
+			err = os.Remove(path)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+
+			return os.Symlink(string(target), path)
+		}
+
+		mode, err := file.Mode.ToOSFileMode()
+		if err != nil {
+			return err
+		}
+
+		out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm())
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+
+		_, err = io.Copy(out, contents)
+		return err
+	})
+}
+
+// templateManifestAt loads the manifest the template had at the given
+// revision, or its current manifest if no revision is provided.
+func (s *manager) templateManifestAt(ctx context.Context, t Template, rev string) (TemplateManifest, error) {
+	if rev == "" {
+		return t.Manifest(ctx)
+	}
+
+	repo, err := s.openTemplateRepository(ctx, t)
+	if err != nil {
+		return TemplateManifest{}, err
+	}
+
+	commit, err := resolveTemplateRevision(repo, rev)
+	if err != nil {
+		return TemplateManifest{}, err
+	}
+
+	file, err := commit.File(TemplateManifestFileName)
+	if err != nil {
+		if errors.Is(err, object.ErrFileNotFound) {
+			return TemplateManifest{}, nil
+		}
+
+		return TemplateManifest{}, err
+	}
+
+	contents, err := file.Contents()
+	if err != nil {
+		return TemplateManifest{}, err
+	}
+
+	return parseTemplateManifest([]byte(contents))
+}
@@ -0,0 +1,181 @@
+package manager
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/letstrygo/letstry/internal/logging"
+)
+
+// TemplateHooks are commands run at specific points in the lifecycle of a
+// session created from a template.
+type TemplateHooks struct {
+	// Commands run inside the session directory after the template has been
+	// copied and before the editor is launched. Each command is rendered with
+	// the template variables, which are quoted for the shell, and run using
+	// the systems shell.
+	PostCreate []string `json:"post_create,omitempty"`
+}
+
+func (s *manager) runPostCreateHooks(ctx context.Context, source Source, dir string, variables map[string]string) error {
+	if source.SourceType != SessionSourceTypeTemplate {
+		return nil
+	}
+
+	name, rev := ParseTemplateReference(source.Value)
+
+	template, err := s.GetTemplate(ctx, name.String())
+	if err != nil {
+		return err
+	}
+
+	manifest, err := s.templateManifestAt(ctx, template, rev)
+	if err != nil {
+		return err
+	}
+
+	logger, err := logging.LoggerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range manifest.Hooks.PostCreate {
+		command, err := renderHook("post_create hook", hook, variables)
+		if err != nil {
+			return err
+		}
+
+		logger.Printf("running post_create hook: %s\n", command)
+		err = runHook(ctx, dir, command)
+		if err != nil {
+			return fmt.Errorf("post_create hook %q failed: %v", command, err)
+		}
+	}
+
+	return nil
+}
+
+// renderHook renders the hook command with the template variables. The
+// output of every action is quoted for the shell, so that the variables can
+// not change the command being run, while the variables used within actions,
+// such as conditions, keep their values.
+func renderHook(name string, hook string, variables map[string]string) (string, error) {
+	tmpl, err := template.New(name).
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"shellquote": shellQuote}).
+		Parse(hook)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %v", name, err)
+	}
+
+	for _, t := range tmpl.Templates() {
+		quoteActions(t.Tree.Root)
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, variables); err != nil {
+		return "", fmt.Errorf("failed to render %s: %v", name, err)
+	}
+
+	return out.String(), nil
+}
+
+// quoteActions pipes the output of every action in the node through
+// shellquote.
+func quoteActions(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+
+		for _, child := range n.Nodes {
+			quoteActions(child)
+		}
+	case *parse.ActionNode:
+		// Actions declaring or assigning variables don't output anything.
+		if len(n.Pipe.Decl) > 0 {
+			return
+		}
+
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{parse.NewIdentifier("shellquote").SetPos(n.Pos)},
+		})
+	case *parse.IfNode:
+		quoteActions(n.List)
+		quoteActions(n.ElseList)
+	case *parse.RangeNode:
+		quoteActions(n.List)
+		quoteActions(n.ElseList)
+	case *parse.WithNode:
+		quoteActions(n.List)
+		quoteActions(n.ElseList)
+	}
+}
+
+// shellQuote quotes value as a single word for the shell used to run hooks.
+func shellQuote(value any) string {
+	s := fmt.Sprint(value)
+
+	if runtime.GOOS == "windows" {
+		return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// runHook runs the command in the given directory using the systems shell,
+// streaming its output through the logger.
+func runHook(ctx context.Context, dir string, command string) error {
+	logger, err := logging.LoggerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	default:
+		shell := os.Getenv("SHELL")
+		if shell == "" {
+			shell = "/bin/sh"
+		}
+		cmd = exec.CommandContext(ctx, shell, "-c", command)
+	}
+
+	pr, pw := io.Pipe()
+	cmd.Dir = dir
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		hookLogger := logger.ChildLogger("hook")
+		scanner := bufio.NewScanner(pr)
+		for scanner.Scan() {
+			hookLogger.Println(scanner.Text())
+		}
+
+		// Drain anything left over so the command never blocks on write.
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+
+	err = cmd.Run()
+	pw.Close()
+	<-done
+
+	return err
+}
@@ -0,0 +1,81 @@
+package manager
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+)
+
+func TestRenderHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are quoted for cmd on windows")
+	}
+
+	tests := []struct {
+		name      string
+		hook      string
+		variables map[string]string
+		want      string
+	}{
+		{
+			name:      "plain value",
+			hook:      "echo {{.name}}",
+			variables: map[string]string{"name": "app"},
+			want:      "app\n",
+		},
+		{
+			name:      "command substitution",
+			hook:      "echo {{.name}}",
+			variables: map[string]string{"name": "$(echo pwned)"},
+			want:      "$(echo pwned)\n",
+		},
+		{
+			name:      "command separator",
+			hook:      "echo {{.name}}",
+			variables: map[string]string{"name": "a; echo pwned"},
+			want:      "a; echo pwned\n",
+		},
+		{
+			name:      "single quotes",
+			hook:      "echo {{.name}}",
+			variables: map[string]string{"name": "it's"},
+			want:      "it's\n",
+		},
+		{
+			name:      "empty value",
+			hook:      "printf '[%s]' {{.name}}",
+			variables: map[string]string{"name": ""},
+			want:      "[]",
+		},
+		{
+			name:      "condition",
+			hook:      `{{if eq .docker "yes"}}echo docker{{else}}echo {{.name}}{{end}}`,
+			variables: map[string]string{"docker": "yes", "name": "app"},
+			want:      "docker\n",
+		},
+		{
+			name:      "variable declaration",
+			hook:      "{{$n := .name}}echo {{$n}}",
+			variables: map[string]string{"name": "a b"},
+			want:      "a b\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command, err := renderHook("hook", tt.hook, tt.variables)
+			if err != nil {
+				t.Fatalf("renderHook() error = %v", err)
+			}
+
+			out, err := exec.Command("/bin/sh", "-c", command).Output()
+			if err != nil {
+				t.Fatalf("running %q: %v", command, err)
+			}
+
+			if string(out) != tt.want {
+				t.Errorf("running %q = %q, want %q", command, out, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/letstrygo/letstry/internal/util/prompt"
+)
+
+const (
+	TemplateManifestFileName = "letstry.json"
+)
+
+var (
+	ErrInvalidTemplateValue = errors.New("invalid template value, must be formatted as key=value")
+)
+
+type TemplateVariable struct {
+	// The name of the variable, used as the key when supplying values.
+	Name string `json:"name"`
+	// A human readable description, displayed when prompting for the value.
+	Description string `json:"description,omitempty"`
+	// The value used when no value is supplied.
+	Default string `json:"default,omitempty"`
+	// A regular expression the value must match.
+	Pattern string `json:"pattern,omitempty"`
+	// When enabled, an empty value is not accepted.
+	Required bool `json:"required,omitempty"`
+}
+
+func (v TemplateVariable) Label() string {
+	if v.Description != "" {
+		return fmt.Sprintf("%s (%s)", v.Name, v.Description)
+	}
+
+	return v.Name
+}
+
+// Validate returns an error if the value is not acceptable for the variable.
+func (v TemplateVariable) Validate(value string) error {
+	if v.Required && value == "" {
+		return fmt.Errorf("template variable %s is required", v.Name)
+	}
+
+	if v.Pattern != "" {
+		re, err := regexp.Compile(v.Pattern)
+		if err != nil {
+			return fmt.Errorf("template variable %s has an invalid pattern: %v", v.Name, err)
+		}
+
+		if !re.MatchString(value) {
+			return fmt.Errorf("value %q for template variable %s does not match pattern %s", value, v.Name, v.Pattern)
+		}
+	}
+
+	return nil
+}
+
+// TemplateCondition controls whether the files matching Path are included
+// in new sessions.
+type TemplateCondition struct {
+	// A glob pattern matching the files or directories the condition applies to.
+	Path string `json:"path"`
+	// A text/template expression evaluated against the template variables.
+	// The files are excluded when it renders to "", "false", "no" or "0".
+	If string `json:"if"`
+}
+
+// TemplateManifest describes a template. It is read from the letstry.json
+// file at the root of the template, if one exists.
+//
+// When a template has a manifest, the contents and names of its files are
+// rendered using text/template when creating new sessions.
+type TemplateManifest struct {
+	Variables []TemplateVariable `json:"variables,omitempty"`
+	// Glob patterns for files that are copied without being rendered.
+	Raw []string `json:"raw,omitempty"`
+	// Conditions for including files or directories.
+	Conditions []TemplateCondition `json:"conditions,omitempty"`
+	// Commands run during the lifecycle of sessions created from the template.
+	Hooks TemplateHooks `json:"hooks,omitempty"`
+}
+
+// Manifest loads the manifest for the template. If the template does not
+// contain a manifest, an empty manifest is returned.
+func (t Template) Manifest(ctx context.Context) (TemplateManifest, error) {
+	return LoadTemplateManifest(t.AbsolutePath(ctx))
+}
+
+// LoadTemplateManifest loads the manifest from the root of the given directory.
+func LoadTemplateManifest(dir string) (TemplateManifest, error) {
+	var manifest TemplateManifest
+
+	data, err := os.ReadFile(filepath.Join(dir, TemplateManifestFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return manifest, nil
+		}
+
+		return manifest, fmt.Errorf("failed to read template manifest: %v", err)
+	}
+
+	return parseTemplateManifest(data)
+}
+
+func parseTemplateManifest(data []byte) (TemplateManifest, error) {
+	var manifest TemplateManifest
+
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("failed to parse template manifest: %v", err)
+	}
+
+	for _, variable := range manifest.Variables {
+		if variable.Name == "" {
+			return manifest, fmt.Errorf("template manifest contains a variable without a name")
+		}
+	}
+
+	return manifest, nil
+}
+
+// ResolveVariables determines the value for every variable declared in the
+// manifest. Supplied values take precedence, followed by a prompt when
+// running interactively, followed by the variables default value.
+func (m TemplateManifest) ResolveVariables(values map[string]string) (map[string]string, error) {
+	result := map[string]string{}
+	for key, value := range values {
+		result[key] = value
+	}
+
+	interactive := prompt.IsInteractive()
+
+	for _, variable := range m.Variables {
+		value, supplied := result[variable.Name]
+
+		for !supplied {
+			if !interactive {
+				value = variable.Default
+				break
+			}
+
+			var err error
+			value, err = prompt.String(variable.Label(), variable.Default)
+			if err != nil {
+				return nil, err
+			}
+
+			// Re-prompt until we receive a valid value.
+			if err := variable.Validate(value); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				continue
+			}
+
+			break
+		}
+
+		if err := variable.Validate(value); err != nil {
+			return nil, err
+		}
+
+		result[variable.Name] = value
+	}
+
+	return result, nil
+}
+
+// ParseTemplateValue parses a value formatted as key=value.
+func ParseTemplateValue(v string) (string, string, error) {
+	key, value, ok := strings.Cut(v, "=")
+	if !ok || key == "" {
+		return "", "", ErrInvalidTemplateValue
+	}
+
+	return key, value, nil
+}
+
+// LoadTemplateValuesFile reads template values from a JSON file containing
+// a single object of string values.
+func LoadTemplateValuesFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read values file: %v", err)
+	}
+
+	values := map[string]string{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse values file: %v", err)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,212 @@
+package manager
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+var (
+	ErrUnsafeTemplatePath = errors.New("rendered template path is outside of the session")
+)
+
+// renderTemplate copies the template directory at src into dest, rendering
+// file contents and path names using the supplied variables.
+//
+// Files and directories excluded by the manifest's conditions are skipped,
+// and files matching one of the manifest's raw patterns are copied verbatim.
+func renderTemplate(manifest TemplateManifest, src string, dest string, variables map[string]string) error {
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+
+		if rel == "." {
+			return nil
+		}
+
+		relSlash := filepath.ToSlash(rel)
+
+		// Don't include repository information if the source
+		// is a git repository, or the templates history.
+		if d.IsDir() && isTemplateRepository(d.Name()) {
+			return filepath.SkipDir
+		}
+
+		included, err := manifest.isIncluded(relSlash, variables)
+		if err != nil {
+			return err
+		}
+
+		if !included {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		renderedRel, err := renderPath(relSlash, variables)
+		if err != nil {
+			return err
+		}
+
+		// A path segment that renders to an empty string excludes the file.
+		if renderedRel == "" {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		target := filepath.Join(dest, filepath.FromSlash(renderedRel))
+		if !isWithinDirectory(dest, target) {
+			return fmt.Errorf("%w: %s", ErrUnsafeTemplatePath, renderedRel)
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+
+			return os.Symlink(link, target)
+		}
+
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		if relSlash != TemplateManifestFileName && !manifest.isRaw(relSlash) {
+			data, err = renderBytes(relSlash, data, variables)
+			if err != nil {
+				return err
+			}
+		}
+
+		return os.WriteFile(target, data, info.Mode().Perm())
+	})
+}
+
+// isIncluded evaluates the manifest's conditions for the given path.
+func (m TemplateManifest) isIncluded(rel string, variables map[string]string) (bool, error) {
+	for _, condition := range m.Conditions {
+		if !matchTemplatePattern(condition.Path, rel) {
+			continue
+		}
+
+		result, err := renderString(fmt.Sprintf("condition %s", condition.Path), condition.If, variables)
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(strings.TrimSpace(result)) {
+		case "", "false", "no", "0":
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// isRaw returns true if the path matches one of the manifest's raw patterns.
+func (m TemplateManifest) isRaw(rel string) bool {
+	for _, pattern := range m.Raw {
+		if matchTemplatePattern(pattern, rel) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchTemplatePattern matches a slash separated relative path against a
+// glob pattern. Patterns without a slash match the name of the file or any
+// of its parent directories, otherwise the pattern must match the path, or
+// one of its parent directories, from the root of the template.
+func matchTemplatePattern(pattern string, rel string) bool {
+	pattern = strings.Trim(pattern, "/")
+	segments := strings.Split(rel, "/")
+
+	for i := range segments {
+		var candidate string
+		if strings.Contains(pattern, "/") {
+			candidate = strings.Join(segments[:i+1], "/")
+		} else {
+			candidate = segments[i]
+		}
+
+		if matched, _ := path.Match(pattern, candidate); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func renderPath(rel string, variables map[string]string) (string, error) {
+	segments := strings.Split(rel, "/")
+	for i, segment := range segments {
+		if !strings.Contains(segment, "{{") {
+			continue
+		}
+
+		rendered, err := renderString(rel, segment, variables)
+		if err != nil {
+			return "", err
+		}
+
+		if rendered == "" {
+			return "", nil
+		}
+
+		// Variables must not be able to move a file to another directory.
+		if rendered == "." || rendered == ".." || strings.ContainsAny(rendered, `/\`) {
+			return "", fmt.Errorf("%w: %s renders to %q", ErrUnsafeTemplatePath, rel, rendered)
+		}
+
+		segments[i] = rendered
+	}
+
+	return strings.Join(segments, "/"), nil
+}
+
+func renderString(name string, value string, variables map[string]string) (string, error) {
+	result, err := renderBytes(name, []byte(value), variables)
+	return string(result), err
+}
+
+func renderBytes(name string, data []byte, variables map[string]string) ([]byte, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template file %s: %v", name, err)
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, variables); err != nil {
+		return nil, fmt.Errorf("failed to render template file %s: %v", name, err)
+	}
+
+	return out.Bytes(), nil
+}
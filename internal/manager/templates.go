@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/fatih/color"
 )
@@ -32,6 +33,28 @@ func (t Template) FormattedString(ctx context.Context) string {
 	return fmt.Sprintf("name=%s, updated=%s", name, updated)
 }
 
+// TemplateDetails describes a template in a form suitable for encoding.
+type TemplateDetails struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// The last time the template was modified, if known.
+	Updated *time.Time `json:"updated,omitempty"`
+}
+
+func (t Template) Details(ctx context.Context) TemplateDetails {
+	details := TemplateDetails{
+		Name: t.String(),
+		Path: t.AbsolutePath(ctx),
+	}
+
+	if stat, err := os.Stat(details.Path); err == nil {
+		updated := stat.ModTime()
+		details.Updated = &updated
+	}
+
+	return details
+}
+
 func (t Template) AbsolutePath(ctx context.Context) string {
 	sessionMgr, err := GetManager(ctx)
 	if err != nil {
@@ -0,0 +1,322 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/otiai10/copy"
+
+	"github.com/letstrygo/letstry/internal/config"
+	"github.com/letstrygo/letstry/internal/util/identifier"
+)
+
+const (
+	trashDirectory     = "trash"
+	trashEntryFileName = "entry.json"
+	trashContentsName  = "contents"
+
+	// Entries are never purged within this period of being moved to the
+	// trash, so that sessions larger than the maximum size can still be
+	// restored.
+	trashGracePeriod = time.Hour
+)
+
+var (
+	ErrTrashEntryNotFound = errors.New("trash entry not found")
+)
+
+type TrashEntryType string
+
+func (t TrashEntryType) String() string {
+	return string(t)
+}
+
+const (
+	TrashEntryTypeSession  TrashEntryType = "session"
+	TrashEntryTypeTemplate TrashEntryType = "template"
+)
+
+// TrashEntry is a session or template that has been moved to the trash.
+type TrashEntry struct {
+	ID        identifier.ID  `json:"id"`
+	Type      TrashEntryType `json:"type"`
+	DeletedAt time.Time      `json:"deleted_at"`
+	Size      int64          `json:"size"`
+	// The location the contents were moved from.
+	OriginalPath string `json:"original_path"`
+	// Set when Type is session.
+	Session *Session `json:"session,omitempty"`
+	// Set when Type is template.
+	Template Template `json:"template,omitempty"`
+}
+
+func (e TrashEntry) String() string {
+	var name string
+	switch e.Type {
+	case TrashEntryTypeSession:
+		name = e.Session.String()
+	case TrashEntryTypeTemplate:
+		name = fmt.Sprintf("template=%s", color.YellowString(e.Template.String()))
+	}
+
+	deleted := color.BlueString("(%s)", e.DeletedAt.Format("2006-01-02 15:04:05"))
+	return fmt.Sprintf("id=%s, type=%s, deleted=%s, %s", e.ID.FormattedString(), e.Type, deleted, name)
+}
+
+func (e TrashEntry) storagePath() string {
+	return filepath.Join(trashDirectory, e.ID.String())
+}
+
+// trashSession moves the directory for the session into the trash.
+func (s *manager) trashSession(ctx context.Context, session Session) error {
+	return s.moveToTrash(ctx, TrashEntry{
+		Type:         TrashEntryTypeSession,
+		OriginalPath: session.Location,
+		Session:      &session,
+	})
+}
+
+// trashTemplate moves the template into the trash.
+func (s *manager) trashTemplate(ctx context.Context, t Template) error {
+	return s.moveToTrash(ctx, TrashEntry{
+		Type:         TrashEntryTypeTemplate,
+		OriginalPath: t.AbsolutePath(ctx),
+		Template:     t,
+	})
+}
+
+func (s *manager) moveToTrash(ctx context.Context, entry TrashEntry) error {
+	entry.ID = identifier.NewID()
+	entry.DeletedAt = time.Now()
+
+	size, err := directorySize(entry.OriginalPath)
+	if err != nil {
+		return fmt.Errorf("failed to move %s to trash: %v", entry.OriginalPath, err)
+	}
+	entry.Size = size
+
+	err = s.storage.CreateDirectory(entry.storagePath())
+	if err != nil {
+		return err
+	}
+
+	entryDir := s.storage.GetAbsolutePath(entry.storagePath())
+
+	// The entry is written before its contents are moved, so that contents
+	// in the trash always have an entry describing where they came from.
+	data, err := json.MarshalIndent(entry, "", "    ")
+	if err != nil {
+		_ = os.RemoveAll(entryDir)
+		return err
+	}
+
+	err = os.WriteFile(filepath.Join(entryDir, trashEntryFileName), data, 0644)
+	if err != nil {
+		_ = os.RemoveAll(entryDir)
+		return fmt.Errorf("failed to move %s to trash: %v", entry.OriginalPath, err)
+	}
+
+	err = moveDirectory(entry.OriginalPath, filepath.Join(entryDir, trashContentsName))
+	if err != nil {
+		_ = os.RemoveAll(entryDir)
+		return fmt.Errorf("failed to move %s to trash: %v", entry.OriginalPath, err)
+	}
+
+	return s.PurgeTrash(ctx)
+}
+
+// ListTrash returns the entries in the trash, oldest first.
+func (s *manager) ListTrash(ctx context.Context) ([]TrashEntry, error) {
+	if !s.storage.DirectoryExists(trashDirectory) {
+		return []TrashEntry{}, nil
+	}
+
+	dirs, err := s.storage.ListDirectories(trashDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []TrashEntry{}
+	for _, dir := range dirs {
+		data, err := os.ReadFile(filepath.Join(s.storage.GetAbsolutePath(trashDirectory), dir, trashEntryFileName))
+		if err != nil {
+			// Skip entries that are still being written.
+			continue
+		}
+
+		var entry TrashEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("failed to decode trash entry %s: %v", dir, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.Before(entries[j].DeletedAt)
+	})
+
+	return entries, nil
+}
+
+// PurgeTrash removes entries older than the configured maximum age, followed
+// by the oldest entries until the trash is within the configured maximum
+// size. Entries moved to the trash within the grace period are kept, and do
+// not count towards the maximum size.
+func (s *manager) PurgeTrash(ctx context.Context) error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	entries, err := s.ListTrash(ctx)
+	if err != nil {
+		return err
+	}
+
+	purgeable := []TrashEntry{}
+	for _, entry := range entries {
+		if time.Since(entry.DeletedAt) >= trashGracePeriod {
+			purgeable = append(purgeable, entry)
+		}
+	}
+
+	var total int64
+	for _, entry := range purgeable {
+		total += entry.Size
+	}
+
+	for _, entry := range purgeable {
+		expired := time.Since(entry.DeletedAt) > cfg.Trash.GetMaxAge()
+		if !expired && total <= cfg.Trash.GetMaxSize() {
+			continue
+		}
+
+		err := s.storage.DeleteDirectory(entry.storagePath())
+		if err != nil {
+			return err
+		}
+
+		total -= entry.Size
+	}
+
+	return nil
+}
+
+// RestoreTrashEntry moves the entry back to its original location. Restored
+// sessions are re-opened in their editor and registered again.
+func (s *manager) RestoreTrashEntry(ctx context.Context, id identifier.ID) (TrashEntry, error) {
+	entries, err := s.ListTrash(ctx)
+	if err != nil {
+		return TrashEntry{}, err
+	}
+
+	for _, entry := range entries {
+		if entry.ID != id {
+			continue
+		}
+
+		switch entry.Type {
+		case TrashEntryTypeTemplate:
+			if s.storage.DirectoryExists(entry.Template.StoragePath()) {
+				return entry, fmt.Errorf("template already exists: %s", entry.Template.String())
+			}
+
+			err = s.createTemplatesDirectoryIfNotExists()
+			if err != nil {
+				return entry, err
+			}
+		case TrashEntryTypeSession:
+			if _, err := s.GetSession(ctx, entry.Session.ID); err == nil {
+				return entry, fmt.Errorf("session with ID %s already exists", entry.Session.ID)
+			}
+		}
+
+		if _, err := os.Stat(entry.OriginalPath); err == nil {
+			return entry, fmt.Errorf("path %s already exists", entry.OriginalPath)
+		}
+
+		entryDir := s.storage.GetAbsolutePath(entry.storagePath())
+
+		err = moveDirectory(filepath.Join(entryDir, trashContentsName), entry.OriginalPath)
+		if err != nil {
+			return entry, fmt.Errorf("failed to restore %s: %v", entry.ID, err)
+		}
+
+		err = s.storage.DeleteDirectory(entry.storagePath())
+		if err != nil {
+			return entry, err
+		}
+
+		if entry.Type == TrashEntryTypeSession {
+			return entry, s.reopenSession(ctx, *entry.Session)
+		}
+
+		return entry, nil
+	}
+
+	return TrashEntry{}, ErrTrashEntryNotFound
+}
+
+// reopenSession launches the editor for a session whose directory already
+// exists and begins monitoring it.
+func (s *manager) reopenSession(ctx context.Context, session Session) error {
+	cmd, err := s.launchEditor(ctx, session.Editor, session.ID, session.Source, session.Location)
+	if err != nil {
+		return err
+	}
+
+	restored, err := s.prepareMonitor(ctx, session.ID, cmd, session.Editor, session.Source, session.Location, session.Variables)
+	if err != nil {
+		return err
+	}
+
+	return s.superviseSession(ctx, cmd, restored)
+}
+
+// moveDirectory renames src to dest, falling back to copying when they are
+// on different devices.
+func moveDirectory(src string, dest string) error {
+	err := os.Rename(src, dest)
+	if err == nil || !isCrossDeviceError(err) {
+		return err
+	}
+
+	if err := copy.Copy(src, dest); err != nil {
+		_ = os.RemoveAll(dest)
+		return err
+	}
+
+	return os.RemoveAll(src)
+}
+
+func directorySize(path string) (int64, error) {
+	var size int64
+
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return size, err
+}
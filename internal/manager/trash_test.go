@@ -0,0 +1,61 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMoveDirectory(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "src")
+	dest := filepath.Join(root, "dest")
+
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "sub", "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := moveDirectory(src, dest); err != nil {
+		t.Fatalf("moveDirectory() error = %v", err)
+	}
+
+	if got := readTestFile(t, filepath.Join(dest, "sub", "a.txt")); got != "a" {
+		t.Errorf("a.txt = %q, want %q", got, "a")
+	}
+
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Errorf("source still exists after move: %v", err)
+	}
+}
+
+func TestMoveDirectoryKeepsSourceOnFailure(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "src")
+	dest := filepath.Join(root, "dest")
+
+	for _, dir := range []string{src, dest} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, filepath.Base(dir)+".txt"), []byte(dir), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Renaming onto a directory that is not empty fails, and must not fall
+	// back to copying into it or removing the source.
+	if err := moveDirectory(src, dest); err == nil {
+		t.Fatal("moveDirectory() error = nil, want an error")
+	}
+
+	if got := readTestFile(t, filepath.Join(src, "src.txt")); got != src {
+		t.Errorf("src.txt = %q, want %q", got, src)
+	}
+
+	if _, err := os.Stat(filepath.Join(dest, "src.txt")); !os.IsNotExist(err) {
+		t.Errorf("source was copied into the destination: %v", err)
+	}
+}
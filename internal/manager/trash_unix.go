@@ -0,0 +1,12 @@
+//go:build !windows
+
+package manager
+
+import (
+	"errors"
+	"syscall"
+)
+
+func isCrossDeviceError(err error) bool {
+	return errors.Is(err, syscall.EXDEV)
+}
@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var (
+	ErrLocked = errors.New("file is locked by another process")
+)
+
+// Lock is an advisory lock held on a file within the storage directory.
+type Lock struct {
+	file *os.File
+}
+
+// Unlock releases the lock.
+func (l *Lock) Unlock() error {
+	defer l.file.Close()
+	return unlockFile(l.file)
+}
+
+// Lock acquires an exclusive advisory lock for the named file, blocking
+// until it becomes available. The lock is held on a separate ".lock" file so
+// that it survives the file itself being replaced.
+func (s *Storage) Lock(name string) (*Lock, error) {
+	return s.lock(name, lockFile)
+}
+
+// TryLock acquires an exclusive advisory lock for the named file, returning
+// ErrLocked if it is already held.
+func (s *Storage) TryLock(name string) (*Lock, error) {
+	return s.lock(name, tryLockFile)
+}
+
+func (s *Storage) lock(name string, acquire func(*os.File) error) (*Lock, error) {
+	lockPath := filepath.Join(s.dir, name+".lock")
+
+	file, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %v", err)
+	}
+
+	if err := acquire(file); err != nil {
+		file.Close()
+		if errors.Is(err, ErrLocked) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("failed to lock %s: %v", name, err)
+	}
+
+	return &Lock{file: file}, nil
+}
+
+// WriteFile atomically replaces the contents of the named file while holding
+// its lock.
+func (s *Storage) WriteFile(name string, data []byte) error {
+	lock, err := s.Lock(name)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	return s.writeFileAtomic(name, data)
+}
+
+// Update performs a locked read-modify-write of the named file. The current
+// contents of the file, or defaultContent if it does not exist, are passed to
+// update and the file is atomically replaced with the result.
+func (s *Storage) Update(name string, defaultContent []byte, update func([]byte) ([]byte, error)) error {
+	lock, err := s.Lock(name)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	data, err := os.ReadFile(filepath.Join(s.dir, name))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read %s: %v", name, err)
+		}
+
+		data = defaultContent
+	}
+
+	data, err = update(data)
+	if err != nil {
+		return err
+	}
+
+	return s.writeFileAtomic(name, data)
+}
+
+// writeFileAtomic writes the data to a temporary file in the same directory
+// and renames it over the named file so readers never observe a partially
+// written file.
+func (s *Storage) writeFileAtomic(name string, data []byte) error {
+	filePath := filepath.Join(s.dir, name)
+
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), "."+filepath.Base(name)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	tmpPath := tmp.Name()
+
+	// Remove the temporary file if anything below fails.
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %v", name, err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync %s: %v", name, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %v", name, err)
+	}
+
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return fmt.Errorf("failed to set permissions on %s: %v", name, err)
+	}
+
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		return fmt.Errorf("failed to replace %s: %v", name, err)
+	}
+
+	return nil
+}
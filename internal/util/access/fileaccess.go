@@ -9,6 +9,50 @@ import (
 )
 
 func IsPathUse(path string) bool {
+	return PathsInUse([]string{path})[path]
+}
+
+// PathsInUse reports, for each of the given paths, whether any process is
+// currently using the path or a file beneath it. On Linux all paths are
+// checked in a single pass over /proc.
+func PathsInUse(paths []string) map[string]bool {
+	result := make(map[string]bool, len(paths))
+
+	switch runtime.GOOS {
+	case "linux":
+		pids, err := ProcessesUsingPaths(paths)
+		if err != nil {
+			// Assume the paths are in use if we can't tell otherwise.
+			for _, path := range paths {
+				result[path] = true
+			}
+			return result
+		}
+
+		for _, path := range paths {
+			result[path] = len(pids[path]) > 0
+		}
+	default:
+		for _, path := range paths {
+			result[path] = isPathInUse(path)
+		}
+	}
+
+	return result
+}
+
+// ProcessesUsingPaths returns the PIDs of the processes holding files under
+// each of the given paths, or whose working directory is beneath them. It is
+// only supported on Linux.
+func ProcessesUsingPaths(paths []string) (map[string][]int, error) {
+	if runtime.GOOS != "linux" {
+		return nil, fmt.Errorf("process scanning is not supported on %s", runtime.GOOS)
+	}
+
+	return scanProcFS(paths)
+}
+
+func isPathInUse(path string) bool {
 	switch runtime.GOOS {
 	case "windows":
 		newPath := fmt.Sprintf("%s-%v", path, time.Now().Unix())
@@ -0,0 +1,77 @@
+package access
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const procRoot = "/proc"
+
+// scanProcFS walks /proc/*/cwd and /proc/*/fd once, returning the PIDs of
+// the processes holding files under each of the given paths. Processes that
+// cannot be inspected (for example, those owned by other users) are skipped.
+func scanProcFS(paths []string) (map[string][]int, error) {
+	result := make(map[string][]int, len(paths))
+
+	targets := make(map[string]string, len(paths))
+	for _, path := range paths {
+		targets[path] = normalizePath(path)
+	}
+
+	entries, err := os.ReadDir(procRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	self := os.Getpid()
+
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || pid == self {
+			continue
+		}
+
+		procDir := filepath.Join(procRoot, entry.Name())
+
+		links := []string{}
+		if cwd, err := os.Readlink(filepath.Join(procDir, "cwd")); err == nil {
+			links = append(links, cwd)
+		}
+
+		fdDir := filepath.Join(procDir, "fd")
+		if fds, err := os.ReadDir(fdDir); err == nil {
+			for _, fd := range fds {
+				if link, err := os.Readlink(filepath.Join(fdDir, fd.Name())); err == nil {
+					links = append(links, link)
+				}
+			}
+		}
+
+		for path, target := range targets {
+			for _, link := range links {
+				if isUnder(link, target) {
+					result[path] = append(result[path], pid)
+					break
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// normalizePath resolves symlinks so that paths can be compared against the
+// links in /proc, which always refer to the real location of a file.
+func normalizePath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+
+	return filepath.Clean(path)
+}
+
+func isUnder(path string, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
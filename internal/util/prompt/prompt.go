@@ -0,0 +1,71 @@
+package prompt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+var (
+	ErrNotInteractive = errors.New("not running in an interactive terminal")
+)
+
+var reader = bufio.NewReader(os.Stdin)
+
+// IsInteractive returns true when both stdin and stderr are attached to a
+// terminal, meaning the user can be prompted for input.
+func IsInteractive() bool {
+	return isTerminal(os.Stdin) && isTerminal(os.Stderr)
+}
+
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// String prompts the user for a value, returning defaultValue when the user
+// enters nothing.
+func String(label string, defaultValue string) (string, error) {
+	if !IsInteractive() {
+		return "", ErrNotInteractive
+	}
+
+	if defaultValue != "" {
+		fmt.Fprintf(os.Stderr, "%s %s: ", color.HiWhiteString(label), color.BlueString("[%s]", defaultValue))
+	} else {
+		fmt.Fprintf(os.Stderr, "%s: ", color.HiWhiteString(label))
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", fmt.Errorf("failed to read input: %v", err)
+	}
+
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return defaultValue, nil
+	}
+
+	return line, nil
+}
+
+// Confirm asks the user a yes or no question, returning false unless the
+// user answers yes.
+func Confirm(label string) (bool, error) {
+	answer, err := String(fmt.Sprintf("%s %s", label, color.BlueString("[y/N]")), "")
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true, nil
+	}
+
+	return false, nil
+}